@@ -3,7 +3,8 @@ package godist
 import (
 	"fmt"
 	"math"
-	"math/rand"
+
+	"github.com/e-dard/godist/special"
 )
 
 // A Beta distribution is a continuous probability distribution on the
@@ -11,9 +12,13 @@ import (
 //
 // Beta distributions have many uses, with one of the more common ones
 // being to model random variables.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
 type Beta struct {
 	Alpha float64
 	Beta  float64
+	Rand  Rand
 }
 
 // Mean returns the mean of the Beta distribution, i.e., α / (α + β)
@@ -27,38 +32,27 @@ func (beta Beta) Mean() (float64, error) {
 // Median returns the median of the Beta distribution.
 //
 // Since there is no closed-form expression for the median of a Beta
-// distribution, we use a number of known closed-form special cases, and
-// an approximation for the general case where α > 1 and β > 1.
-//
-// Currently, Median cannot calculate the median where α and β are < 1
-// unless α = β.
+// distribution, Median is calculated as Quantile(0.5).
 func (beta Beta) Median() (float64, error) {
-	aa, bb := beta.Alpha, beta.Beta
+	return beta.Quantile(0.5)
+}
+
+// Quantile returns the p-quantile of the Beta distribution, i.e., the
+// value x such that CDF(x) = p.
+//
+// Quantile numerically inverts the regularized incomplete beta function
+// to full float64 precision, and is supported for all valid values of α
+// and β.
+func (beta Beta) Quantile(p float64) (float64, error) {
 	if ok, err := beta.valid(); !ok {
 		return 0, err
 	}
 
-	if aa == 1 && bb > 0 {
-		return 1.0 - math.Pow(2.0, -1/bb), nil
-	} else if aa > 0 && bb == 1 {
-		return math.Pow(2.0, -1/aa), nil
-	} else if aa == 3 && bb == 2 {
-		return 0.6142724318, nil
-	} else if aa == 2 && bb == 3 {
-		return 0.3857275681, nil
-	}
-
-	if aa == bb {
-		return 0.5, nil
-	} else if aa < 1 && bb < 1 {
-		msg := fmt.Sprintf("Median not supported for Beta Distribution [α = %v, β = %v]", aa, bb)
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
 		return 0, UnsupportedError{S: msg}
 	}
-
-	// when α > 1 and β > 1 use approximation
-	// Kerman J (2011) - "A closed-form approximation for the median of
-	// 					  the beta distribution"
-	return (aa - 1.0/3.0) / (aa + bb - (2.0 / 3.0)), nil
+	return special.InvRegIncBeta(beta.Alpha, beta.Beta, p), nil
 }
 
 // Mode returns the mode of the Beta distribution.
@@ -84,6 +78,258 @@ func (beta Beta) Variance() (float64, error) {
 	return (aa * bb) / (math.Pow(aa+bb, 2) * (aa + bb + 1)), nil
 }
 
+// Skewness returns the skewness of the Beta Distribution.
+func (beta Beta) Skewness() (float64, error) {
+	aa, bb := beta.Alpha, beta.Beta
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+	return 2 * (bb - aa) * math.Sqrt(aa+bb+1) / ((aa + bb + 2) * math.Sqrt(aa*bb)), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Beta Distribution.
+func (beta Beta) ExcessKurtosis() (float64, error) {
+	aa, bb := beta.Alpha, beta.Beta
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+	num := 6 * ((aa-bb)*(aa-bb)*(aa+bb+1) - aa*bb*(aa+bb+2))
+	return num / (aa * bb * (aa + bb + 2) * (aa + bb + 3)), nil
+}
+
+// Entropy returns the differential entropy of the Beta Distribution in
+// nats, i.e.,
+//
+//	ln B(α, β) - (α - 1)ψ(α) - (β - 1)ψ(β) + (α + β - 2)ψ(α + β)
+//
+// where ψ is the digamma function.
+func (beta Beta) Entropy() (float64, error) {
+	aa, bb := beta.Alpha, beta.Beta
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+	return special.LogBeta(aa, bb) - (aa-1)*special.Digamma(aa) - (bb-1)*special.Digamma(bb) + (aa+bb-2)*special.Digamma(aa+bb), nil
+}
+
+// KL returns the Kullback-Leibler divergence of other from the Beta
+// Distribution in nats, i.e., the expected value under beta of
+// ln(p(x) / q(x)), where p and q are the densities of beta and other.
+func (beta Beta) KL(other Beta) (float64, error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	} else if ok, err := other.valid(); !ok {
+		return 0, err
+	}
+
+	a1, b1, a2, b2 := beta.Alpha, beta.Beta, other.Alpha, other.Beta
+	return special.LogBeta(a2, b2) - special.LogBeta(a1, b1) + (a1-a2)*special.Digamma(a1) + (b1-b2)*special.Digamma(b1) +
+		(a2-a1+b2-b1)*special.Digamma(a1+b1), nil
+}
+
+// PDF returns the value of the probability density function of the Beta
+// distribution at x.
+//
+// The density is zero outside of [0, 1]. At the boundaries the density
+// may be infinite, e.g., at x = 0 when α < 1.
+func (beta Beta) PDF(x float64) (float64, error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+
+	if x < 0 || x > 1 {
+		return 0, nil
+	}
+
+	lp, err := beta.LogPDF(x)
+	return math.Exp(lp), err
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function of the Beta distribution at x.
+//
+// LogPDF is more accurate than taking the logarithm of PDF when the
+// density is very small, and returns -Inf outside of [0, 1].
+func (beta Beta) LogPDF(x float64) (float64, error) {
+	aa, bb := beta.Alpha, beta.Beta
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+
+	if x < 0 || x > 1 {
+		return math.Inf(-1), nil
+	}
+
+	var lx, l1x float64
+	if aa != 1 {
+		lx = (aa - 1) * math.Log(x)
+	}
+	if bb != 1 {
+		l1x = (bb - 1) * math.Log1p(-x)
+	}
+	return lx + l1x - special.LogBeta(aa, bb), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Beta distribution at x, i.e., the regularized incomplete beta function
+// I_x(α, β).
+func (beta Beta) CDF(x float64) (float64, error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+	return special.RegIncBeta(beta.Alpha, beta.Beta, x), nil
+}
+
+// CredibleInterval returns the equal-tailed interval containing the
+// provided probability mass of the Beta distribution, i.e., the interval
+// between the (1 - mass) / 2 and (1 + mass) / 2 quantiles.
+func (beta Beta) CredibleInterval(mass float64) (lower, upper float64, err error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, 0, err
+	}
+	if err := validMass(mass); err != nil {
+		return 0, 0, err
+	}
+
+	lower = special.InvRegIncBeta(beta.Alpha, beta.Beta, (1-mass)/2)
+	upper = special.InvRegIncBeta(beta.Alpha, beta.Beta, (1+mass)/2)
+	return lower, upper, nil
+}
+
+// HDI returns the highest density interval containing the provided
+// probability mass of the Beta distribution, i.e., the narrowest
+// interval [Q(p), Q(p + mass)].
+//
+// The interval width is minimised over p by bisection on the sign of its
+// derivative, which is zero where the density at each end of the
+// interval is equal.
+//
+// For U-shaped Beta distributions (α, β < 1) the region of highest
+// density is not a single interval, but the union of intervals at each
+// boundary. In that case HDI returns the narrowest single interval
+// containing mass, which always lies against one of the boundaries.
+func (beta Beta) HDI(mass float64) (lower, upper float64, err error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, 0, err
+	}
+	if err := validMass(mass); err != nil {
+		return 0, 0, err
+	}
+
+	aa, bb := beta.Alpha, beta.Beta
+	width := func(p float64) float64 {
+		return special.InvRegIncBeta(aa, bb, p+mass) - special.InvRegIncBeta(aa, bb, p)
+	}
+
+	// the derivative of the width with respect to p is
+	// 1/f(Q(p + mass)) - 1/f(Q(p)), which has the same sign as
+	// log f(Q(p)) - log f(Q(p + mass)).
+	slope := func(p float64) float64 {
+		fl, _ := beta.LogPDF(special.InvRegIncBeta(aa, bb, p))
+		fu, _ := beta.LogPDF(special.InvRegIncBeta(aa, bb, p+mass))
+		return fl - fu
+	}
+
+	lo, hi := 0.0, 1-mass
+	p := lo
+	if slope(lo) < 0 && slope(hi) > 0 {
+		for i := 0; i < 200 && hi-lo > epsilon*hi; i++ {
+			mid := lo + (hi-lo)/2
+			if slope(mid) < 0 {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		p = lo + (hi-lo)/2
+	}
+
+	// the width may instead be minimised at either boundary, where the
+	// density is monotonic or U-shaped.
+	for _, end := range []float64{0, 1 - mass} {
+		if width(end) < width(p) {
+			p = end
+		}
+	}
+	return special.InvRegIncBeta(aa, bb, p), special.InvRegIncBeta(aa, bb, p+mass), nil
+}
+
+// validMass returns an error if mass is not a probability in (0, 1].
+func validMass(mass float64) error {
+	if !(mass > 0 && mass <= 1) {
+		msg := fmt.Sprintf("Interval not supported for mass = %v", mass)
+		return UnsupportedError{S: msg}
+	}
+	return nil
+}
+
+// Observe returns the posterior distribution formed by updating the Beta
+// distribution, as a conjugate prior for a Binomial proportion, with the
+// provided numbers of successes and failures, i.e.,
+// Beta(α + successes, β + failures).
+func (beta Beta) Observe(successes, failures int) Beta {
+	beta.Alpha += float64(successes)
+	beta.Beta += float64(failures)
+	return beta
+}
+
+// ProbabilityGreaterThan returns P(X > Y), where X is a random variable
+// distributed according to beta, and Y independently according to
+// other.
+//
+// Where either α is a (reasonably small) integer, P(X > Y) is calculated
+// exactly using a closed-form sum, as described by Evan Miller in
+// "Formulas for Bayesian A/B Testing" (2014). Otherwise, it is
+// calculated by numerical integration.
+func (beta Beta) ProbabilityGreaterThan(other Beta) (float64, error) {
+	if ok, err := beta.valid(); !ok {
+		return 0, err
+	}
+	if ok, err := other.valid(); !ok {
+		return 0, err
+	}
+
+	if isSmallInt(beta.Alpha) {
+		return betaGreaterSum(beta, other), nil
+	} else if isSmallInt(other.Alpha) {
+		return 1 - betaGreaterSum(other, beta), nil
+	}
+	return betaGreaterIntegral(beta, other), nil
+}
+
+// maximum number of terms evaluated by betaGreaterSum.
+const maxBetaSumTerms = 100000
+
+// isSmallInt returns true if v is an integer which can be used as the
+// number of terms in betaGreaterSum.
+func isSmallInt(v float64) bool {
+	return v == math.Trunc(v) && v <= maxBetaSumTerms
+}
+
+// betaGreaterSum returns P(X > Y), where X ~ x and Y ~ y, and x.Alpha is
+// an integer.
+func betaGreaterSum(x, y Beta) float64 {
+	var sum float64
+	lby := special.LogBeta(y.Alpha, y.Beta)
+	for i := 0.0; i < x.Alpha; i++ {
+		sum += math.Exp(special.LogBeta(y.Alpha+i, x.Beta+y.Beta) - math.Log(x.Beta+i) -
+			special.LogBeta(1+i, x.Beta) - lby)
+	}
+	return sum
+}
+
+// betaGreaterIntegral returns P(X > Y), where X ~ x and Y ~ y, by
+// numerically integrating F_Y(Q_X(u)) over u ∈ [0, 1], where F_Y is the
+// CDF of y and Q_X the quantile function of x.
+//
+// Unlike the more direct integral of f_X(t)F_Y(t), the integrand is
+// bounded even when x has an unbounded density.
+func betaGreaterIntegral(x, y Beta) float64 {
+	f := func(u float64) float64 {
+		return special.RegIncBeta(y.Alpha, y.Beta, special.InvRegIncBeta(x.Alpha, x.Beta, u))
+	}
+	return integrate(f, 0, 1, 1e-12)
+}
+
 // Float64 returns a random variate from the Beta Distribution.
 //
 // Float64 makes use of four different algorithms for generating random
@@ -99,36 +345,47 @@ func (beta Beta) Float64() (float64, error) {
 
 	if b < 0.5 {
 		// Jöhnk (1964)
-		return genBetaJohnk(aa, bb), nil
+		return genBetaJohnk(beta.Rand, aa, bb), nil
 	} else if a <= 1.0 {
 		// Cheng BC (1978)
-		return genBetaChengBC(aa, bb, a, b), nil
+		return genBetaChengBC(beta.Rand, aa, bb, a, b), nil
 
 	}
 	// Cheng BB (1978)
-	return genBetaChengBB(aa, bb, a, b), nil
+	return genBetaChengBB(beta.Rand, aa, bb, a, b), nil
 }
 
 // genBetaJohnk generates a random variate from a Beta distribution with
 // shape parameters aa and bb, according to Jöhnk's algorithm, described
 // by Dagpunar in "Principles of Random Variate Generation" (1988).
-func genBetaJohnk(aa, bb float64) float64 {
-	u, y := rand.Float64(), rand.Float64()
-	return math.Pow(u, 1/aa) / (math.Pow(u, 1/aa) + math.Pow(y, 1/bb))
+//
+// Candidates are only accepted when u^(1/aa) + y^(1/bb) ≤ 1, and the
+// calculation is carried out on a log scale, since for small shape
+// parameters both terms can underflow.
+func genBetaJohnk(rnd Rand, aa, bb float64) float64 {
+	for {
+		lx := math.Log(randFloat64(rnd)) / aa
+		ly := math.Log(randFloat64(rnd)) / bb
+		lm := math.Max(lx, ly)
+		lsum := lm + math.Log(math.Exp(lx-lm)+math.Exp(ly-lm))
+		if lsum <= 0 {
+			return math.Exp(lx - lsum)
+		}
+	}
 }
 
 // genBetaChengBB generates a random variate from a Beta distribution
 // with shape parameters aa and bb, according to Cheng's BB algorithm,
 // described in "Generating beta variates with non-integral shape
 // parameters" (1978).
-func genBetaChengBB(aa, bb, a, b float64) float64 {
+func genBetaChengBB(rnd Rand, aa, bb, a, b float64) float64 {
 	alpha := a + b
 	beta := math.Sqrt((alpha - 2.0) / (2.0*a*b - alpha))
 	gamma := a + 1.0/beta
 
 	var r, s, t, v, w, z float64
 	complete := func() bool {
-		u1, u2 := rand.Float64(), rand.Float64()
+		u1, u2 := randFloat64(rnd), randFloat64(rnd)
 
 		v = beta * math.Log(u1/(1.0-u1))
 		if v <= 709.78 {
@@ -170,7 +427,7 @@ func genBetaChengBB(aa, bb, a, b float64) float64 {
 // with shape parameters aa and bb, according to Cheng's BC algorithm,
 // described in "Generating beta variates with non-integral shape
 // parameters" (1978).
-func genBetaChengBC(aa, bb, a, b float64) float64 {
+func genBetaChengBC(rnd Rand, aa, bb, a, b float64) float64 {
 	var u1, u2, v, w, y, z float64
 	alpha := a + b
 	beta := 1.0 / a
@@ -191,7 +448,7 @@ func genBetaChengBC(aa, bb, a, b float64) float64 {
 	}
 
 	for {
-		u1, u2 = rand.Float64(), rand.Float64()
+		u1, u2 = randFloat64(rnd), randFloat64(rnd)
 		if u1 < 0.5 {
 			y = u1 * u2
 			z = u1 * y
@@ -2,7 +2,9 @@ package godist
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
+	randv2 "math/rand/v2"
 	"testing"
 	"time"
 )
@@ -15,6 +17,7 @@ type betaExample struct {
 
 func Test_Beta_Imp_Distribution(t *testing.T) {
 	var _ Distribution = Beta{}
+	var _ Moments = Beta{}
 }
 
 func Test_Beta_Mean(t *testing.T) {
@@ -50,17 +53,15 @@ func Test_Beta_Median(t *testing.T) {
 		betaExample{in: Beta{Alpha: 0.1, Beta: 1}, out: 0.0009765625},
 		betaExample{in: Beta{Alpha: 1, Beta: 1}, out: 0.5},
 		betaExample{in: Beta{Alpha: 2, Beta: 2}, out: 0.5},
-		betaExample{in: Beta{Alpha: 3, Beta: 2}, out: 0.6142724318},
-		betaExample{in: Beta{Alpha: 2, Beta: 3}, out: 0.3857275681},
-		betaExample{in: Beta{Alpha: 20, Beta: 18}, out: 0.5267857142857143},
+		betaExample{in: Beta{Alpha: 3, Beta: 2}, out: 0.6142724318676105},
+		betaExample{in: Beta{Alpha: 2, Beta: 3}, out: 0.38572756813238956},
+		betaExample{in: Beta{Alpha: 20, Beta: 18}, out: 0.5267822825983265},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 0.5}, out: 0.5},
+		betaExample{in: Beta{Alpha: 0.1, Beta: 0.1}, out: 0.5},
 		betaExample{
 			in:  Beta{Alpha: 0, Beta: 0},
 			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 0]"),
 		},
-		betaExample{
-			in:  Beta{Alpha: 0.1, Beta: 0.9},
-			err: fmt.Errorf("Median not supported for Beta Distribution [α = 0.1, β = 0.9]"),
-		},
 	}
 
 	for _, ex := range examples {
@@ -75,6 +76,72 @@ func Test_Beta_Median(t *testing.T) {
 	}
 }
 
+// Median must be supported for all valid shape parameters, including
+// those where α, β < 1 and α ≠ β.
+func Test_Beta_Median_CDF(t *testing.T) {
+	inputs := []Beta{
+		Beta{Alpha: 0.1, Beta: 0.9},
+		Beta{Alpha: 0.9, Beta: 0.1},
+		Beta{Alpha: 0.01, Beta: 0.5},
+		Beta{Alpha: 0.5, Beta: 3},
+		Beta{Alpha: 1000, Beta: 0.7},
+	}
+
+	for _, b := range inputs {
+		med, err := b.Median()
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		actual, _ := b.CDF(med)
+		if !floatsPicoEqual(actual, 0.5) {
+			t.Fatalf("expected %v\n got %v for %#v\n", 0.5, actual, b)
+		}
+	}
+}
+
+func Test_Beta_Quantile(t *testing.T) {
+	type Example struct {
+		in  Beta
+		p   float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: Beta{Alpha: 1, Beta: 1}, p: 0.3, out: 0.3},
+		Example{in: Beta{Alpha: 2, Beta: 5}, p: 0, out: 0},
+		Example{in: Beta{Alpha: 2, Beta: 5}, p: 1, out: 1},
+		Example{in: Beta{Alpha: 2, Beta: 5}, p: 0.9, out: 0.5103163065514916},
+		Example{in: Beta{Alpha: 10, Beta: 3}, p: 0.01, out: 0.4626570856830752},
+		Example{in: Beta{Alpha: 5, Beta: 5}, p: 0.99, out: 0.8290348945175409},
+		Example{in: Beta{Alpha: 0.5, Beta: 0.5}, p: 0.25, out: 0.14644660940672624},
+		Example{in: Beta{Alpha: 0.2, Beta: 1}, p: 0.5, out: 0.03125},
+		Example{in: Beta{Alpha: 1, Beta: 0.25}, p: 0.75, out: 0.99609375},
+		Example{
+			in:  Beta{Alpha: 2, Beta: 5},
+			p:   1.1,
+			err: fmt.Errorf("Quantile not supported for p = 1.1"),
+		},
+		Example{
+			in:  Beta{Alpha: 0, Beta: 5},
+			p:   0.5,
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 5]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Quantile(ex.p)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
 func Test_Beta_Mode(t *testing.T) {
 	examples := []betaExample{
 		betaExample{in: Beta{Alpha: 2, Beta: 2}, out: 0.5},
@@ -126,6 +193,409 @@ func Test_Beta_Variance(t *testing.T) {
 	}
 }
 
+func Test_Beta_Skewness(t *testing.T) {
+	examples := []betaExample{
+		betaExample{in: Beta{Alpha: 1, Beta: 1}, out: 0},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 0.5}, out: 0},
+		betaExample{in: Beta{Alpha: 2, Beta: 5}, out: 0.5962847939999439},
+		betaExample{in: Beta{Alpha: 5, Beta: 2}, out: -0.5962847939999439},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 3}, out: 1.5745916432444338},
+		betaExample{
+			in:  Beta{Alpha: 0, Beta: 2.0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 2]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Skewness()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_ExcessKurtosis(t *testing.T) {
+	examples := []betaExample{
+		betaExample{in: Beta{Alpha: 1, Beta: 1}, out: -1.2},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 0.5}, out: -1.5},
+		betaExample{in: Beta{Alpha: 2, Beta: 5}, out: -0.12},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 3}, out: 2.2237762237762237},
+		betaExample{
+			in:  Beta{Alpha: 2.0, Beta: 0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 2, β = 0]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.ExcessKurtosis()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_Entropy(t *testing.T) {
+	examples := []betaExample{
+		betaExample{in: Beta{Alpha: 1, Beta: 1}, out: 0},
+		betaExample{in: Beta{Alpha: 2, Beta: 2}, out: -0.12509280256138822},
+		betaExample{in: Beta{Alpha: 0.5, Beta: 0.5}, out: -0.2415644752704905},
+		betaExample{
+			in:  Beta{Alpha: 0, Beta: 2.0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 2]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Entropy()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_KL(t *testing.T) {
+	type Example struct {
+		p, q Beta
+		err  error
+		out  float64
+	}
+
+	examples := []Example{
+		Example{p: Beta{Alpha: 3, Beta: 4}, q: Beta{Alpha: 3, Beta: 4}, out: 0},
+		Example{p: Beta{Alpha: 2, Beta: 2}, q: Beta{Alpha: 1, Beta: 1}, out: 0.12509280256138822},
+		Example{p: Beta{Alpha: 1, Beta: 1}, q: Beta{Alpha: 2, Beta: 2}, out: 0.20824053077194504},
+		Example{
+			p:   Beta{Alpha: 1, Beta: 1},
+			q:   Beta{Alpha: 2, Beta: 0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 2, β = 0]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.p.KL(ex.q)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_PDF(t *testing.T) {
+	type Example struct {
+		in  Beta
+		x   float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 0.7, out: 1},
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 1.2, out: 0},
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: -0.1, out: 0},
+		Example{in: Beta{Alpha: 2, Beta: 2}, x: 0.5, out: 1.5},
+		Example{in: Beta{Alpha: 2, Beta: 5}, x: 0.3, out: 2.1609},
+		Example{in: Beta{Alpha: 2, Beta: 5}, x: 0, out: 0},
+		Example{in: Beta{Alpha: 1, Beta: 3}, x: 0, out: 3},
+		Example{in: Beta{Alpha: 0.5, Beta: 0.5}, x: 0.5, out: 0.6366197723675814},
+		Example{in: Beta{Alpha: 10, Beta: 10}, x: 0.3, out: 0.7337400214305961},
+		Example{in: Beta{Alpha: 30, Beta: 20}, x: 0.7, out: 2.116500750474034},
+		Example{
+			in:  Beta{Alpha: 0, Beta: 2.0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 2]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.PDF(ex.x)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_LogPDF(t *testing.T) {
+	type Example struct {
+		in  Beta
+		x   float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 0.7, out: 0},
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 1.2, out: math.Inf(-1)},
+		Example{in: Beta{Alpha: 2, Beta: 2}, x: 0.5, out: 0.4054651081081644},
+		Example{in: Beta{Alpha: 2, Beta: 5}, x: 0.3, out: 0.7705248015812898},
+		Example{in: Beta{Alpha: 0.5, Beta: 0.5}, x: 0.5, out: -0.4515827052894548},
+		Example{in: Beta{Alpha: 10, Beta: 10}, x: 0.3, out: -0.3096005073454606},
+		Example{in: Beta{Alpha: 30, Beta: 20}, x: 0.7, out: 0.74976413556136},
+		Example{
+			in:  Beta{Alpha: 2, Beta: 0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 2, β = 0]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.LogPDF(ex.x)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if actual != ex.out && !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_CDF(t *testing.T) {
+	type Example struct {
+		in  Beta
+		x   float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 0.7, out: 0.7},
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: -1, out: 0},
+		Example{in: Beta{Alpha: 1, Beta: 1}, x: 2, out: 1},
+		Example{in: Beta{Alpha: 2, Beta: 3}, x: 0.5, out: 0.6875},
+		Example{in: Beta{Alpha: 2, Beta: 5}, x: 0.3, out: 0.579825},
+		Example{in: Beta{Alpha: 5, Beta: 2}, x: 0.9, out: 0.885735},
+		Example{in: Beta{Alpha: 1, Beta: 3}, x: 0.2, out: 0.488},
+		Example{in: Beta{Alpha: 0.5, Beta: 0.5}, x: 0.25, out: 1.0 / 3.0},
+		Example{in: Beta{Alpha: 10, Beta: 10}, x: 0.3, out: 0.032553356881300954},
+		Example{in: Beta{Alpha: 30, Beta: 20}, x: 0.7, out: 0.9300129066994927},
+		Example{
+			in:  Beta{Alpha: 0, Beta: 0},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 0]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.CDF(ex.x)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Beta_CredibleInterval(t *testing.T) {
+	type Example struct {
+		in           Beta
+		mass         float64
+		err          error
+		lower, upper float64
+	}
+
+	examples := []Example{
+		Example{in: Beta{Alpha: 1, Beta: 1}, mass: 0.9, lower: 0.05, upper: 0.95},
+		Example{in: Beta{Alpha: 1, Beta: 1}, mass: 1, lower: 0, upper: 1},
+		Example{in: Beta{Alpha: 0.5, Beta: 0.5}, mass: 0.5, lower: 0.14644660940672624, upper: 0.8535533905932737},
+		Example{in: Beta{Alpha: 2, Beta: 1}, mass: 0.5, lower: 0.5, upper: 0.8660254037844386},
+		Example{
+			in:   Beta{Alpha: 1, Beta: 1},
+			mass: 0,
+			err:  fmt.Errorf("Interval not supported for mass = 0"),
+		},
+		Example{
+			in:   Beta{Alpha: 0, Beta: 1},
+			mass: 0.9,
+			err:  fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 1]"),
+		},
+	}
+
+	for _, ex := range examples {
+		lower, upper, err := ex.in.CredibleInterval(ex.mass)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(lower, ex.lower) || !floatsPicoEqual(upper, ex.upper) {
+			t.Fatalf("expected [%v, %v]\n got [%v, %v]\n", ex.lower, ex.upper, lower, upper)
+		}
+	}
+}
+
+func Test_Beta_HDI(t *testing.T) {
+	type Example struct {
+		in           Beta
+		mass         float64
+		lower, upper float64
+	}
+
+	examples := []Example{
+		// symmetric, so equal to the credible interval.
+		Example{in: Beta{Alpha: 2, Beta: 2}, mass: 0.5, lower: 0.32635182233306964, upper: 0.6736481776669303},
+		// monotonically decreasing density.
+		Example{in: Beta{Alpha: 1, Beta: 3}, mass: 0.9, lower: 0, upper: 0.5358411166387223},
+		// monotonically increasing density.
+		Example{in: Beta{Alpha: 2, Beta: 1}, mass: 0.75, lower: 0.5, upper: 1},
+	}
+
+	for _, ex := range examples {
+		lower, upper, err := ex.in.HDI(ex.mass)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsNanoEqual(lower, ex.lower) || !floatsNanoEqual(upper, ex.upper) {
+			t.Fatalf("expected [%v, %v]\n got [%v, %v]\n", ex.lower, ex.upper, lower, upper)
+		}
+	}
+
+	if _, _, err := (Beta{Alpha: 2, Beta: 2}).HDI(1.5); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+// for skewed unimodal distributions, the density at each end of the HDI
+// must be equal, and it must be narrower than the credible interval.
+func Test_Beta_HDI_Skewed(t *testing.T) {
+	inputs := []Beta{
+		Beta{Alpha: 2, Beta: 5},
+		Beta{Alpha: 30, Beta: 3},
+		Beta{Alpha: 1.5, Beta: 100},
+	}
+
+	for _, b := range inputs {
+		for _, mass := range []float64{0.5, 0.9, 0.99} {
+			lower, upper, _ := b.HDI(mass)
+			cl, cu, _ := b.CredibleInterval(mass)
+			if upper-lower >= cu-cl {
+				t.Fatalf("expected narrower than %v\n got %v for %v\n", cu-cl, upper-lower, b)
+			}
+
+			fl, _ := b.PDF(lower)
+			fu, _ := b.PDF(upper)
+			if !floatsEqual(fl, fu, 1e-6*fl) {
+				t.Fatalf("expected %v\n got %v for %v\n", fl, fu, b)
+			}
+
+			pl, _ := b.CDF(lower)
+			pu, _ := b.CDF(upper)
+			if !floatsPicoEqual(pu-pl, mass) {
+				t.Fatalf("expected %v\n got %v for %v\n", mass, pu-pl, b)
+			}
+		}
+	}
+}
+
+// for U-shaped distributions, the HDI must be the narrower of the
+// intervals against each boundary.
+func Test_Beta_HDI_UShaped(t *testing.T) {
+	inputs := []Beta{
+		Beta{Alpha: 0.5, Beta: 0.8},
+		Beta{Alpha: 0.9, Beta: 0.2},
+		Beta{Alpha: 0.1, Beta: 0.1},
+	}
+
+	for _, b := range inputs {
+		for _, mass := range []float64{0.5, 0.9} {
+			left, _ := b.Quantile(mass)
+			right, _ := b.Quantile(1 - mass)
+			expLower, expUpper := 0.0, left
+			if 1-right < left {
+				expLower, expUpper = right, 1
+			}
+
+			lower, upper, _ := b.HDI(mass)
+			if !floatsPicoEqual(lower, expLower) || !floatsPicoEqual(upper, expUpper) {
+				t.Fatalf("expected [%v, %v]\n got [%v, %v] for %v\n", expLower, expUpper, lower, upper, b)
+			}
+		}
+	}
+}
+
+func Test_Beta_Observe(t *testing.T) {
+	prior := Beta{Alpha: 1, Beta: 1}
+	actual := prior.Observe(12, 30).Observe(3, 0)
+	if actual.Alpha != 16 || actual.Beta != 31 {
+		t.Fatalf("expected %v\n got %v\n", Beta{Alpha: 16, Beta: 31}, actual)
+	}
+
+	// the prior must not be modified.
+	if prior.Alpha != 1 || prior.Beta != 1 {
+		t.Fatalf("expected %v\n got %v\n", Beta{Alpha: 1, Beta: 1}, prior)
+	}
+}
+
+func Test_Beta_ProbabilityGreaterThan(t *testing.T) {
+	type Example struct {
+		x, y Beta
+		err  error
+		out  float64
+	}
+
+	examples := []Example{
+		Example{x: Beta{Alpha: 1, Beta: 1}, y: Beta{Alpha: 1, Beta: 1}, out: 0.5},
+		Example{x: Beta{Alpha: 2, Beta: 1}, y: Beta{Alpha: 1, Beta: 1}, out: 2.0 / 3.0},
+		Example{x: Beta{Alpha: 1, Beta: 2}, y: Beta{Alpha: 1, Beta: 1}, out: 1.0 / 3.0},
+		Example{x: Beta{Alpha: 1.5, Beta: 1.5}, y: Beta{Alpha: 2, Beta: 1}, out: 0.3125},
+		Example{x: Beta{Alpha: 0.5, Beta: 0.5}, y: Beta{Alpha: 1, Beta: 1}, out: 0.5},
+		Example{x: Beta{Alpha: 2.5, Beta: 1}, y: Beta{Alpha: 1, Beta: 1}, out: 2.5 / 3.5},
+		// neither α is an integer.
+		Example{x: Beta{Alpha: 2.5, Beta: 1}, y: Beta{Alpha: 1.5, Beta: 1}, out: 0.625},
+		Example{x: Beta{Alpha: 0.3, Beta: 0.7}, y: Beta{Alpha: 0.3, Beta: 0.7}, out: 0.5},
+		Example{
+			x:   Beta{Alpha: 1, Beta: 1},
+			y:   Beta{Alpha: 0, Beta: 1},
+			err: fmt.Errorf("Invalid Beta Distribution: [α = 0, β = 1]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.x.ProbabilityGreaterThan(ex.y)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsNanoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v for %v > %v\n", ex.out, actual, ex.x, ex.y)
+		}
+	}
+}
+
+// the closed-form sum and numerical integration must agree.
+func Test_Beta_ProbabilityGreaterThan_Integral(t *testing.T) {
+	inputs := [][2]Beta{
+		{Beta{Alpha: 13, Beta: 87}, Beta{Alpha: 9, Beta: 91}},
+		{Beta{Alpha: 1, Beta: 0.5}, Beta{Alpha: 0.5, Beta: 0.5}},
+		{Beta{Alpha: 201, Beta: 1799}, Beta{Alpha: 230, Beta: 1770}},
+		{Beta{Alpha: 3, Beta: 0.2}, Beta{Alpha: 40, Beta: 2}},
+	}
+
+	for _, in := range inputs {
+		exp := betaGreaterSum(in[0], in[1])
+		if actual := betaGreaterIntegral(in[0], in[1]); !floatsNanoEqual(actual, exp) {
+			t.Fatalf("expected %v\n got %v for %v > %v\n", exp, actual, in[0], in[1])
+		}
+	}
+}
+
 // tests random variate generation for values using Jöhnk's algorithm
 func Test_Beta_Float64(t *testing.T) {
 	inputs := []Beta{
@@ -167,6 +637,73 @@ func Test_Beta_Float64(t *testing.T) {
 	}
 }
 
+// Jöhnk's algorithm only accepts some of its candidates, without which
+// the variates are biased for asymmetric shape parameters.
+func Test_Beta_Float64_Johnk(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for _, b := range []Beta{{Alpha: 0.3, Beta: 0.4}, {Alpha: 0.45, Beta: 0.25}, {Alpha: 0.35, Beta: 0.48}} {
+		b.Rand = rnd
+		e := &Empirical{}
+		for i := 0; i < 20000; i++ {
+			v, _ := b.Float64()
+			e.Add(v)
+		}
+
+		res, err := KolmogorovSmirnov(e, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 1e-3 {
+			t.Fatalf("expected p-value above %v\n got %v for %#v\n", 1e-3, res.PValue, b)
+		}
+	}
+}
+
+// a seeded Rand must produce the same sequence of variates for each of
+// the generation algorithms.
+func Test_Beta_Float64_Rand(t *testing.T) {
+	type Example struct {
+		in  Beta
+		out []float64
+	}
+
+	examples := []Example{
+		// Jöhnk
+		Example{
+			in:  Beta{Alpha: 0.3, Beta: 0.4, Rand: rand.New(rand.NewSource(42))},
+			out: []float64{0.9709210261339631, 0.9034043416291688, 0.0003262149478091474},
+		},
+		// Cheng BC
+		Example{
+			in:  Beta{Alpha: 0.5, Beta: 3, Rand: rand.New(rand.NewSource(42))},
+			out: []float64{0.32011012499590413, 0.06680325538006407, 0.008754550496568432},
+		},
+		// Cheng BB
+		Example{
+			in:  Beta{Alpha: 10, Beta: 3, Rand: rand.New(rand.NewSource(42))},
+			out: []float64{0.8107934039708958, 0.7309702209547216, 0.9367554262883498},
+		},
+		// math/rand/v2 source
+		Example{
+			in:  Beta{Alpha: 2, Beta: 5, Rand: randv2.New(randv2.NewPCG(1, 2))},
+			out: []float64{0.3872483282405994, 0.29006154017201813, 0.4837479961863484},
+		},
+	}
+
+	for _, ex := range examples {
+		for _, exp := range ex.out {
+			actual, err := ex.in.Float64()
+			if err != nil {
+				t.Fatalf("expected no error\n got %v\n", err)
+			}
+
+			if actual != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, actual)
+			}
+		}
+	}
+}
+
 type dist struct {
 	mean     float64
 	median   float64
@@ -0,0 +1,304 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// A Binomial distribution is a discrete probability distribution of the
+// number of successes in a sequence of N ≥ 0 independent trials, each of
+// which succeeds with probability P ∈ [0, 1].
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Int and Float64. Otherwise the default source in math/rand is used.
+type Binomial struct {
+	N    int
+	P    float64
+	Rand Rand
+}
+
+// Mean returns the mean of the Binomial distribution, i.e., NP.
+func (b Binomial) Mean() (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+	return float64(b.N) * b.P, nil
+}
+
+// Median returns the median of the Binomial distribution, i.e., the
+// smallest k such that CDF(k) ≥ 0.5.
+func (b Binomial) Median() (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+
+	cdf := func(k int) float64 { return b.cdf(k) }
+	return float64(discreteQuantile(cdf, 0.5, int(float64(b.N)*b.P))), nil
+}
+
+// Mode returns the mode of the Binomial distribution, i.e., ⌊(N + 1)P⌋.
+//
+// Where (N + 1)P is an integer the distribution is bi-modal, with modes
+// (N + 1)P and (N + 1)P - 1, in which case the smallest mode is returned.
+func (b Binomial) Mode() (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+	return math.Max(0, math.Ceil(float64(b.N+1)*b.P)-1), nil
+}
+
+// Variance returns the variance of the Binomial distribution, i.e.,
+// NP(1 - P).
+func (b Binomial) Variance() (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+	return float64(b.N) * b.P * (1 - b.P), nil
+}
+
+// Skewness returns the skewness of the Binomial distribution, i.e.,
+// (1 - 2P) / √(NP(1 - P)).
+//
+// The skewness is not supported where the distribution is degenerate,
+// i.e., where N = 0, P = 0 or P = 1.
+func (b Binomial) Skewness() (float64, error) {
+	v, err := b.Variance()
+	if err != nil {
+		return 0, err
+	}
+
+	if v == 0 {
+		msg := fmt.Sprintf("Skewness not supported for Binomial Distribution [n = %v, p = %v]", b.N, b.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return (1 - 2*b.P) / math.Sqrt(v), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Binomial
+// distribution, i.e., (1 - 6P(1 - P)) / NP(1 - P).
+//
+// The kurtosis is not supported where the distribution is degenerate,
+// i.e., where N = 0, P = 0 or P = 1.
+func (b Binomial) ExcessKurtosis() (float64, error) {
+	v, err := b.Variance()
+	if err != nil {
+		return 0, err
+	}
+
+	if v == 0 {
+		msg := fmt.Sprintf("ExcessKurtosis not supported for Binomial Distribution [n = %v, p = %v]", b.N, b.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return (1 - 6*b.P*(1-b.P)) / v, nil
+}
+
+// PMF returns the value of the probability mass function of the
+// Binomial distribution at k.
+func (b Binomial) PMF(k int) (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+
+	if k < 0 || k > b.N {
+		return 0, nil
+	} else if b.P == 0 || b.P == 1 {
+		// degenerate distributions, where all mass is at 0 or N.
+		if (b.P == 0 && k == 0) || (b.P == 1 && k == b.N) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	lc := lfactorial(b.N) - lfactorial(k) - lfactorial(b.N-k)
+	return math.Exp(lc + float64(k)*math.Log(b.P) + float64(b.N-k)*math.Log1p(-b.P)), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Binomial distribution at k, i.e., the regularized incomplete beta
+// function I_1-P(N - k, k + 1).
+func (b Binomial) CDF(k int) (float64, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+	return b.cdf(k), nil
+}
+
+func (b Binomial) cdf(k int) float64 {
+	if k < 0 {
+		return 0
+	} else if k >= b.N {
+		return 1
+	} else if b.P == 0 || b.P == 1 {
+		return 1 - b.P
+	}
+	return special.RegIncBeta(float64(b.N-k), float64(k)+1, 1-b.P)
+}
+
+// Int returns a random variate from the Binomial distribution.
+//
+// Int uses the inversion algorithm where N·min(P, 1 - P) < 30, and
+// Kachitvichyanukul and Schmeiser's BTPE algorithm otherwise.
+func (b Binomial) Int() (int, error) {
+	if ok, err := b.valid(); !ok {
+		return 0, err
+	}
+
+	p := math.Min(b.P, 1-b.P)
+	var k int
+	if p == 0 {
+		k = 0
+	} else if float64(b.N)*p < 30 {
+		k = genBinomialInversion(b.Rand, b.N, p)
+	} else {
+		k = genBinomialBTPE(b.Rand, b.N, p)
+	}
+
+	if p != b.P {
+		return b.N - k, nil
+	}
+	return k, nil
+}
+
+// Float64 returns a random variate from the Binomial distribution. The
+// returned value is always an integer.
+func (b Binomial) Float64() (float64, error) {
+	k, err := b.Int()
+	return float64(k), err
+}
+
+// genBinomialInversion generates a random variate from a Binomial
+// distribution with parameters n and p ≤ 0.5, by sequential search of
+// the inverse CDF.
+func genBinomialInversion(rnd Rand, n int, p float64) int {
+	q := 1 - p
+	qn := math.Exp(float64(n) * math.Log(q))
+	np := float64(n) * p
+	bound := math.Min(float64(n), np+10*math.Sqrt(np*q+1))
+
+	k, px, u := 0, qn, randFloat64(rnd)
+	for u > px {
+		k++
+		if float64(k) > bound {
+			k, px, u = 0, qn, randFloat64(rnd)
+		} else {
+			u -= px
+			px = (float64(n-k+1) * p * px) / (float64(k) * q)
+		}
+	}
+	return k
+}
+
+// genBinomialBTPE generates a random variate from a Binomial
+// distribution with parameters n and p ≤ 0.5, according to the BTPE
+// algorithm described by Kachitvichyanukul and Schmeiser in "Binomial
+// random variate generation" (1988).
+func genBinomialBTPE(rnd Rand, n int, p float64) int {
+	nf, q := float64(n), 1-p
+	fm := nf*p + p
+	m := math.Floor(fm)
+	nrq := nf * p * q
+
+	p1 := math.Floor(2.195*math.Sqrt(nrq)-4.6*q) + 0.5
+	xm := m + 0.5
+	xl, xr := xm-p1, xm+p1
+	c := 0.134 + 20.5/(15.3+m)
+	a := (fm - xl) / (fm - xl*p)
+	laml := a * (1 + a/2)
+	a = (xr - fm) / (xr * q)
+	lamr := a * (1 + a/2)
+	p2 := p1 * (1 + 2*c)
+	p3 := p2 + c/laml
+	p4 := p3 + c/lamr
+
+	for {
+		var y float64
+		u := randFloat64(rnd) * p4
+		v := randFloat64(rnd)
+
+		if u <= p1 {
+			// triangular region, accept immediately.
+			return int(math.Floor(xm - p1*v + u))
+		} else if u <= p2 {
+			// parallelogram region.
+			x := xl + (u-p1)/c
+			v = v*c + 1 - math.Abs(m-x+0.5)/p1
+			if v > 1 {
+				continue
+			}
+			y = math.Floor(x)
+		} else if u <= p3 {
+			// left exponential tail.
+			y = math.Floor(xl + math.Log(v)/laml)
+			if y < 0 || v == 0 {
+				continue
+			}
+			v = v * (u - p2) * laml
+		} else {
+			// right exponential tail.
+			y = math.Floor(xr - math.Log(v)/lamr)
+			if y > nf || v == 0 {
+				continue
+			}
+			v = v * (u - p3) * lamr
+		}
+
+		k := math.Abs(y - m)
+		if k <= 20 || k >= nrq/2-1 {
+			// explicit evaluation of f(y) / f(m).
+			s := p / q
+			a := s * (nf + 1)
+			f := 1.0
+			if m < y {
+				for i := m + 1; i <= y; i++ {
+					f *= a/i - s
+				}
+			} else if m > y {
+				for i := y + 1; i <= m; i++ {
+					f /= a/i - s
+				}
+			}
+
+			if v <= f {
+				return int(y)
+			}
+			continue
+		}
+
+		// squeezing using upper and lower bounds on log(f(y)).
+		rho := (k / nrq) * ((k*(k/3+0.625)+0.1666666666666)/nrq + 0.5)
+		t := -k * k / (2 * nrq)
+		lv := math.Log(v)
+		if lv < t-rho {
+			return int(y)
+		} else if lv > t+rho {
+			continue
+		}
+
+		// final acceptance using Stirling's formula.
+		x1, f1, z, w := y+1, m+1, nf+1-m, nf-y+1
+		x2, f2, z2, w2 := x1*x1, f1*f1, z*z, w*w
+		bound := xm*math.Log(f1/x1) + (nf-m+0.5)*math.Log(z/w) +
+			(y-m)*math.Log(w*p/(x1*q)) +
+			stirlingCorrection(f1, f2) + stirlingCorrection(z, z2) +
+			stirlingCorrection(x1, x2) + stirlingCorrection(w, w2)
+		if lv <= bound {
+			return int(y)
+		}
+	}
+}
+
+// stirlingCorrection returns the correction term of Stirling's formula
+// used by BTPE, where x2 = x².
+func stirlingCorrection(x, x2 float64) float64 {
+	return (13860 - (462-(132-(99-140/x2)/x2)/x2)/x2) / x / 166320
+}
+
+func (b Binomial) valid() (bool, error) {
+	if b.N < 0 || !(b.P >= 0 && b.P <= 1) {
+		msg := fmt.Sprintf("Invalid Binomial Distribution: [n = %v, p = %v]", b.N, b.P)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
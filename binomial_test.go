@@ -0,0 +1,151 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type binomialExample struct {
+	in  Binomial
+	k   int
+	err error
+	out float64
+}
+
+func Test_Binomial_Imp_Distribution(t *testing.T) {
+	var _ Distribution = Binomial{}
+	var _ Moments = Binomial{}
+}
+
+func Test_Binomial_Moments(t *testing.T) {
+	type Example struct {
+		in                           Binomial
+		mean, median, mode, variance float64
+	}
+
+	examples := []Example{
+		Example{in: Binomial{N: 10, P: 0.3}, mean: 3, median: 3, mode: 3, variance: 2.1},
+		Example{in: Binomial{N: 100, P: 0.5}, mean: 50, median: 50, mode: 50, variance: 25},
+		Example{in: Binomial{N: 7, P: 0.9}, mean: 6.3, median: 6, mode: 7, variance: 0.63},
+		// (N + 1)P = 2, so modes are 1 and 2.
+		Example{in: Binomial{N: 3, P: 0.5}, mean: 1.5, median: 1, mode: 1, variance: 0.75},
+		Example{in: Binomial{N: 4, P: 0}, mean: 0, median: 0, mode: 0, variance: 0},
+		Example{in: Binomial{N: 4, P: 1}, mean: 4, median: 4, mode: 4, variance: 0},
+	}
+
+	for _, ex := range examples {
+		mean, _ := ex.in.Mean()
+		median, _ := ex.in.Median()
+		mode, _ := ex.in.Mode()
+		variance, _ := ex.in.Variance()
+
+		actual := []float64{mean, median, mode, variance}
+		expected := []float64{ex.mean, ex.median, ex.mode, ex.variance}
+		for i := range actual {
+			if !floatsPicoEqual(actual[i], expected[i]) {
+				t.Fatalf("expected %v\n got %v for %#v\n", expected, actual, ex.in)
+			}
+		}
+	}
+}
+
+func Test_Binomial_Skewness_Kurtosis(t *testing.T) {
+	b := Binomial{N: 10, P: 0.3}
+	skew, _ := b.Skewness()
+	kurtosis, _ := b.ExcessKurtosis()
+	if !floatsPicoEqual(skew, 0.2760262237369417) || !floatsPicoEqual(kurtosis, -0.12380952380952381) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 0.2760262237369417, -0.12380952380952381, skew, kurtosis)
+	}
+
+	for _, b := range []Binomial{Binomial{N: 0, P: 0.5}, Binomial{N: 4, P: 1}} {
+		if _, err := b.Skewness(); err == nil {
+			t.Fatalf("expected error\n got %v for %#v\n", err, b)
+		}
+		if _, err := b.ExcessKurtosis(); err == nil {
+			t.Fatalf("expected error\n got %v for %#v\n", err, b)
+		}
+	}
+}
+
+func Test_Binomial_Invalid(t *testing.T) {
+	for _, b := range []Binomial{Binomial{N: -1, P: 0.5}, Binomial{N: 1, P: 1.5}} {
+		exp := fmt.Sprintf("Invalid Binomial Distribution: [n = %v, p = %v]", b.N, b.P)
+		fns := []func() (float64, error){b.Mean, b.Median, b.Mode, b.Variance, b.Float64}
+		for _, fn := range fns {
+			if _, err := fn(); err == nil || err.Error() != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, err)
+			}
+		}
+	}
+}
+
+func Test_Binomial_PMF(t *testing.T) {
+	examples := []binomialExample{
+		binomialExample{in: Binomial{N: 10, P: 0.3}, k: 3, out: 0.266827932},
+		binomialExample{in: Binomial{N: 100, P: 0.5}, k: 50, out: 0.07958923738717877},
+		binomialExample{in: Binomial{N: 10, P: 0.3}, k: 11, out: 0},
+		binomialExample{in: Binomial{N: 10, P: 0}, k: 0, out: 1},
+		binomialExample{in: Binomial{N: 10, P: 1}, k: 9, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.PMF(ex.k)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Binomial_CDF(t *testing.T) {
+	examples := []binomialExample{
+		binomialExample{in: Binomial{N: 10, P: 0.3}, k: 3, out: 0.6496107184},
+		binomialExample{in: Binomial{N: 100, P: 0.5}, k: 45, out: 0.18410080866334813},
+		binomialExample{in: Binomial{N: 10, P: 0.3}, k: -1, out: 0},
+		binomialExample{in: Binomial{N: 10, P: 0.3}, k: 10, out: 1},
+		binomialExample{in: Binomial{N: 10, P: 1}, k: 9, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.CDF(ex.k)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Binomial_Int(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := []Binomial{
+		// inversion
+		Binomial{N: 20, P: 0.3},
+		Binomial{N: 50, P: 0.95},
+		// BTPE
+		Binomial{N: 100, P: 0.5},
+		Binomial{N: 1000, P: 0.07},
+		Binomial{N: 5000, P: 0.8},
+	}
+
+	for _, b := range inputs {
+		b.Rand = rnd
+		checkDiscreteVariates(t, b.Int, b.PMF, 100000, 0.02)
+	}
+}
+
+func Test_stirlingCorrection(t *testing.T) {
+	for _, x := range []float64{10, 20, 50, 100} {
+		expected := 1/(12*x) - 1/(360*x*x*x) + 1/(1260*math.Pow(x, 5))
+		if actual := stirlingCorrection(x, x*x); !floatsNanoEqual(actual, expected) {
+			t.Fatalf("expected %v\n got %v for %v\n", expected, actual, x)
+		}
+	}
+}
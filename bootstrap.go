@@ -0,0 +1,230 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// DefaultBootstrapResamples is the number of resamples drawn by
+// BootstrapInterval when none is provided.
+const DefaultBootstrapResamples = 2000
+
+// A BootstrapMethod determines how a bootstrap confidence interval is
+// calculated from the distribution of a resampled statistic.
+type BootstrapMethod int
+
+const (
+	// BootstrapPercentile uses the quantiles of the resampled statistic
+	// as the interval.
+	BootstrapPercentile BootstrapMethod = iota
+
+	// BootstrapBasic reflects the quantiles of the resampled statistic
+	// about the statistic of the original sample, i.e.,
+	// [2θ - Q(1 - α/2), 2θ - Q(α/2)].
+	BootstrapBasic
+
+	// BootstrapBCa uses the bias-corrected and accelerated interval
+	// described by Efron in "Better Bootstrap Confidence Intervals"
+	// (1987), which adjusts the quantiles of the resampled statistic for
+	// its bias and skewness.
+	BootstrapBCa
+)
+
+// BootstrapOptions control how BootstrapInterval resamples a
+// distribution. The zero value is ready to use, and draws
+// DefaultBootstrapResamples resamples on a single goroutine to calculate
+// a percentile interval.
+//
+// Resamples are drawn deterministically for a given Seed, regardless of
+// the number of Workers. Source is optional, and when set is called to
+// provide the source of randomness for the i-th resample, which must not
+// be shared with any other resample. Otherwise each resample uses a PCG
+// generator from math/rand/v2, seeded by Seed and i.
+type BootstrapOptions struct {
+	Resamples int
+	Method    BootstrapMethod
+	Workers   int
+	Seed      uint64
+	Source    func(i int) Rand
+}
+
+// BootstrapInterval returns a bootstrap confidence interval containing
+// the provided probability mass, for a statistic of the distribution
+// that may have no closed form, such as (*Empirical).Median.
+//
+// The sample is resampled with replacement, by drawing values uniformly
+// at random in the same way as Float64, and stat is evaluated on each
+// resample. The BCa method also evaluates stat on each jackknife sample,
+// formed by leaving out a single value. Where Workers is greater than
+// one, resamples are evaluated concurrently on that many goroutines, so
+// stat must be safe to call concurrently on distinct distributions.
+//
+// BootstrapInterval is not supported on sketched or weighted
+// distributions.
+func (e *Empirical) BootstrapInterval(stat func(*Empirical) (float64, error), mass float64,
+	opts BootstrapOptions) (lower, upper float64, err error) {
+	if e.n == 0 {
+		msg := "bootstrap interval cannot be calculated on empty distribution."
+		return 0, 0, InvalidDistributionError{S: msg}
+	} else if e.sketch != nil {
+		msg := "bootstrap interval cannot be calculated on a sketched distribution."
+		return 0, 0, UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "bootstrap interval cannot be calculated on a weighted distribution."
+		return 0, 0, UnsupportedError{S: msg}
+	}
+	if err := validMass(mass); err != nil {
+		return 0, 0, err
+	}
+	if opts.Method < BootstrapPercentile || opts.Method > BootstrapBCa {
+		msg := fmt.Sprintf("bootstrap method %v not supported.", opts.Method)
+		return 0, 0, UnsupportedError{S: msg}
+	}
+
+	resamples := opts.Resamples
+	if resamples <= 0 {
+		resamples = DefaultBootstrapResamples
+	}
+
+	sample := make([]float64, e.tree.len())
+	copy(sample, e.sortedSample())
+
+	theta, err := stat(e)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stats, err := parallelStats(resamples, opts.Workers, func(i int) (float64, error) {
+		var rnd Rand
+		if opts.Source != nil {
+			rnd = opts.Source(i)
+		} else {
+			rnd = rand.New(rand.NewPCG(opts.Seed, uint64(i)))
+		}
+
+		resample := make([]float64, len(sample))
+		for j := range resample {
+			resample[j] = sample[randIntn(rnd, len(sample))]
+		}
+		return stat(empiricalOf(resample))
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	sort.Float64s(stats)
+
+	lo, hi := (1-mass)/2, (1+mass)/2
+	switch opts.Method {
+	case BootstrapBasic:
+		return 2*theta - hyndmanFan(stats, hi, 7), 2*theta - hyndmanFan(stats, lo, 7), nil
+	case BootstrapBCa:
+		lo, hi, err = bcaLevels(sample, stat, theta, stats, lo, hi, opts.Workers)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return hyndmanFan(stats, lo, 7), hyndmanFan(stats, hi, 7), nil
+}
+
+// bcaLevels returns the adjusted probabilities lo and hi, at which the
+// sorted resampled statistics are evaluated to give a BCa interval.
+//
+// The bias correction z₀ is the standard Normal quantile of the
+// proportion of resampled statistics below theta, and the acceleration
+// is estimated from the skewness of the jackknife statistics.
+func bcaLevels(sample []float64, stat func(*Empirical) (float64, error), theta float64,
+	stats []float64, lo, hi float64, workers int) (float64, float64, error) {
+	below := sort.SearchFloat64s(stats, theta)
+	equal := sort.SearchFloat64s(stats, math.Nextafter(theta, math.Inf(1))) - below
+	z0 := stdNormalQuantile((float64(below) + 0.5*float64(equal)) / float64(len(stats)))
+	if math.IsInf(z0, 0) {
+		msg := "BCa interval cannot be calculated when every resampled statistic is on one side of the sample statistic."
+		return 0, 0, UnsupportedError{S: msg}
+	}
+
+	if len(sample) < 2 {
+		msg := "BCa interval cannot be calculated on a single value."
+		return 0, 0, UnsupportedError{S: msg}
+	}
+
+	jack, err := parallelStats(len(sample), workers, func(i int) (float64, error) {
+		loo := make([]float64, 0, len(sample)-1)
+		loo = append(loo, sample[:i]...)
+		loo = append(loo, sample[i+1:]...)
+		return stat(empiricalOf(loo))
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var mean float64
+	for _, v := range jack {
+		mean += v
+	}
+	mean /= float64(len(jack))
+
+	var s2, s3 float64
+	for _, v := range jack {
+		d := mean - v
+		s2 += d * d
+		s3 += d * d * d
+	}
+
+	var a float64
+	if s2 > 0 {
+		a = s3 / (6 * math.Pow(s2, 1.5))
+	}
+
+	level := func(p float64) float64 {
+		z := stdNormalQuantile(p)
+		if math.IsInf(z, 0) {
+			return p
+		}
+		w := z0 + z
+		return 0.5 * math.Erfc(-(z0+w/(1-a*w))/math.Sqrt2)
+	}
+	return level(lo), level(hi), nil
+}
+
+// parallelStats returns the results of calling f for each i in [0, n),
+// on up to workers goroutines. The results are in order of i, and the
+// error returned is that of the smallest i for which f failed.
+func parallelStats(n, workers int, f func(i int) (float64, error)) ([]float64, error) {
+	if workers < 1 {
+		workers = 1
+	} else if workers > n {
+		workers = n
+	}
+
+	stats := make([]float64, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				stats[i], errs[i] = f(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// empiricalOf returns an Empirical distribution of the values in sample.
+func empiricalOf(sample []float64) *Empirical {
+	e := &Empirical{}
+	e.Add(sample...)
+	return e
+}
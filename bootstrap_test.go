@@ -0,0 +1,109 @@
+package godist
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_Empirical_BootstrapInterval(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	n := Normal{Mu: 10, Sigma: 2, Rand: rnd}
+	for i := 0; i < 200; i++ {
+		v, _ := n.Float64()
+		e.Add(v)
+	}
+
+	mean, _ := e.Mean()
+	variance, _ := e.Variance()
+	se := math.Sqrt(variance / 200)
+
+	methods := []BootstrapMethod{BootstrapPercentile, BootstrapBasic, BootstrapBCa}
+	for _, method := range methods {
+		opts := BootstrapOptions{Method: method, Seed: 7}
+		lower, upper, err := e.BootstrapInterval((*Empirical).Mean, 0.95, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the interval for the mean should be close to the Normal
+		// approximation.
+		if !floatsEqual(lower, mean-1.96*se, 0.05) || !floatsEqual(upper, mean+1.96*se, 0.05) {
+			t.Fatalf("expected %v, %v\n got %v, %v for method %v\n", mean-1.96*se, mean+1.96*se, lower, upper, method)
+		}
+
+		// results must not depend on the number of workers.
+		opts.Workers = 4
+		l4, u4, err := e.BootstrapInterval((*Empirical).Mean, 0.95, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l4 != lower || u4 != upper {
+			t.Fatalf("expected %v, %v\n got %v, %v for method %v\n", lower, upper, l4, u4, method)
+		}
+	}
+
+	median, _ := e.Median()
+	lower, upper, err := e.BootstrapInterval((*Empirical).Median, 0.9, BootstrapOptions{Method: BootstrapBCa, Resamples: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(lower < median && median < upper) {
+		t.Fatalf("expected interval around %v\n got %v, %v\n", median, lower, upper)
+	}
+
+	// an injected source is used for each resample.
+	calls := 0
+	opts := BootstrapOptions{Resamples: 10, Source: func(i int) Rand {
+		calls++
+		return rand.New(rand.NewSource(int64(i)))
+	}}
+	if _, _, err := e.BootstrapInterval((*Empirical).Mean, 0.9, opts); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 {
+		t.Fatalf("expected %v\n got %v\n", 10, calls)
+	}
+}
+
+func Test_Empirical_BootstrapInterval_Errors(t *testing.T) {
+	e := &Empirical{}
+	if _, _, err := e.BootstrapInterval((*Empirical).Mean, 0.9, BootstrapOptions{}); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2, 3)
+	if _, _, err := sk.BootstrapInterval((*Empirical).Mean, 0.9, BootstrapOptions{}); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+
+	e.Add(1, 2, 3, 4)
+	if _, _, err := e.BootstrapInterval((*Empirical).Mean, 1.5, BootstrapOptions{}); err == nil {
+		t.Fatal("expected error on invalid mass")
+	}
+	if _, _, err := e.BootstrapInterval((*Empirical).Mean, 0.9, BootstrapOptions{Method: 3}); err == nil {
+		t.Fatal("expected error on invalid method")
+	}
+
+	expected := errors.New("statistic failed")
+	stat := func(*Empirical) (float64, error) { return 0, expected }
+	if _, _, err := e.BootstrapInterval(stat, 0.9, BootstrapOptions{Workers: 3}); err != expected {
+		t.Fatalf("expected %v\n got %v\n", expected, err)
+	}
+
+	// a constant sample has a degenerate interval.
+	c := &Empirical{}
+	c.Add(1, 1, 1)
+	for _, method := range []BootstrapMethod{BootstrapPercentile, BootstrapBasic, BootstrapBCa} {
+		lower, upper, err := c.BootstrapInterval((*Empirical).Median, 0.9, BootstrapOptions{Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lower != 1 || upper != 1 {
+			t.Fatalf("expected %v, %v\n got %v, %v for method %v\n", 1, 1, lower, upper, method)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/e-dard/godist/special"
+)
+
+// minimum expected count in each category of a test against a
+// DiscretePMF, below which adjacent categories are pooled.
+const minExpectedCount = 5
+
+// A DiscretePMF is a discrete distribution on the non-negative integers
+// with a probability mass function and cumulative distribution function,
+// against which a sample can be tested.
+//
+// Poisson, Binomial, Geometric and NegativeBinomial all implement
+// DiscretePMF.
+type DiscretePMF interface {
+	PMF(k int) (float64, error)
+	CDF(k int) (float64, error)
+}
+
+// ChiSquared carries out Pearson's chi-squared test of the null
+// hypothesis that the frequencies of the values in e are consistent with
+// the expected counts, which are keyed by value.
+//
+// The expected counts are scaled so that they sum to the size of the
+// sample, so expected proportions may be provided instead. Every value
+// in the sample must have a positive expected count. The test has one
+// fewer degrees of freedom than there are expected counts, and the
+// p-value is calculated from the asymptotic chi-squared distribution of
+// the statistic, which is reliable where every expected count is at
+// least five.
+func ChiSquared(e *Empirical, expected map[float64]float64) (TestResult, error) {
+	obs, exp, err := expectedCounts(e, expected)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return chiSquaredResult(pearsonStatistic(obs, exp), len(obs)-1), nil
+}
+
+// ChiSquaredPMF carries out Pearson's chi-squared test of the null
+// hypothesis that the sample in e was drawn from dist.
+//
+// Every value in the sample must be a non-negative integer. The expected
+// count of each distinct value in the sample is calculated from the PMF
+// of dist, along with a category for each gap between the values, and a
+// final category for all integers larger than the sample, whose expected
+// counts are calculated from the CDF of dist. Adjacent categories are
+// then pooled until each has an expected count of at least five, so that
+// the asymptotic chi-squared distribution of the statistic can be relied
+// upon.
+func ChiSquaredPMF(e *Empirical, dist DiscretePMF) (TestResult, error) {
+	obs, exp, err := pmfCounts(e, dist)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return chiSquaredResult(pearsonStatistic(obs, exp), len(obs)-1), nil
+}
+
+// GTest carries out a G-test, or likelihood-ratio test, of the null
+// hypothesis that the frequencies of the values in e are consistent with
+// the expected counts, which are keyed by value.
+//
+// The expected counts are treated in the same way as by ChiSquared, and
+// the statistic G = 2 Σ O ln(O / E) has the same asymptotic chi-squared
+// distribution as Pearson's statistic.
+func GTest(e *Empirical, expected map[float64]float64) (TestResult, error) {
+	obs, exp, err := expectedCounts(e, expected)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return chiSquaredResult(gStatistic(obs, exp), len(obs)-1), nil
+}
+
+// GTestPMF carries out a G-test of the null hypothesis that the sample in
+// e was drawn from dist, pooling categories in the same way as
+// ChiSquaredPMF.
+func GTestPMF(e *Empirical, dist DiscretePMF) (TestResult, error) {
+	obs, exp, err := pmfCounts(e, dist)
+	if err != nil {
+		return TestResult{}, err
+	}
+	return chiSquaredResult(gStatistic(obs, exp), len(obs)-1), nil
+}
+
+// expectedCounts returns the observed and expected counts of each value
+// in expected, with the expected counts scaled to the size of the sample
+// in e.
+func expectedCounts(e *Empirical, expected map[float64]float64) ([]float64, []float64, error) {
+	freqs, err := testFrequencies(e)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]float64, 0, len(expected))
+	var total float64
+	for v, c := range expected {
+		if !(c > 0) || math.IsInf(c, 1) {
+			msg := fmt.Sprintf("expected count %v for value %v is not positive.", c, v)
+			return nil, nil, UnsupportedError{S: msg}
+		}
+		values = append(values, v)
+		total += c
+	}
+	sort.Float64s(values)
+
+	if len(values) < 2 {
+		msg := "test requires at least two expected counts."
+		return nil, nil, UnsupportedError{S: msg}
+	}
+
+	counts := make(map[float64]float64, len(freqs))
+	for _, f := range freqs {
+		if _, ok := expected[f.Value]; !ok {
+			msg := fmt.Sprintf("value %v has no expected count.", f.Value)
+			return nil, nil, UnsupportedError{S: msg}
+		}
+		counts[f.Value] = float64(f.Count)
+	}
+
+	obs, exp := make([]float64, len(values)), make([]float64, len(values))
+	for i, v := range values {
+		obs[i] = counts[v]
+		exp[i] = expected[v] * e.n / total
+	}
+	return obs, exp, nil
+}
+
+// pmfCounts returns the observed and expected counts of the integers in
+// the sample in e, under dist, pooled so that each expected count is at
+// least minExpectedCount.
+func pmfCounts(e *Empirical, dist DiscretePMF) ([]float64, []float64, error) {
+	freqs, err := testFrequencies(e)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, f := range freqs {
+		if f.Value < 0 || f.Value != math.Floor(f.Value) || f.Value > math.MaxInt32 {
+			msg := fmt.Sprintf("value %v is not a non-negative integer.", f.Value)
+			return nil, nil, UnsupportedError{S: msg}
+		}
+	}
+
+	// one category for each value in the sample, and for each gap between
+	// them, so that the number of categories does not depend on the size
+	// of the values, and a final category for the remaining mass of dist.
+	var obs, exp []float64
+	var next int
+	var cdf float64
+	for _, f := range freqs {
+		k := int(f.Value)
+		if k > next {
+			c, err := dist.CDF(k - 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			obs, exp = append(obs, 0), append(exp, math.Max(0, c-cdf)*e.n)
+		}
+
+		p, err := dist.PMF(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cdf, err = dist.CDF(k); err != nil {
+			return nil, nil, err
+		}
+		obs, exp = append(obs, float64(f.Count)), append(exp, p*e.n)
+		next = k + 1
+	}
+	obs, exp = append(obs, 0), append(exp, math.Max(0, 1-cdf)*e.n)
+
+	obs, exp = poolCounts(obs, exp, minExpectedCount)
+	if len(obs) < 2 {
+		msg := "test requires at least two categories after pooling."
+		return nil, nil, UnsupportedError{S: msg}
+	}
+	return obs, exp, nil
+}
+
+// poolCounts merges adjacent categories, in order, until each has an
+// expected count of at least minCount. Any remainder at the end is merged
+// into the last category.
+func poolCounts(obs, exp []float64, minCount float64) ([]float64, []float64) {
+	var pobs, pexp []float64
+	var o, x float64
+	for i := range exp {
+		o += obs[i]
+		x += exp[i]
+		if x >= minCount {
+			pobs, pexp = append(pobs, o), append(pexp, x)
+			o, x = 0, 0
+		}
+	}
+
+	if o > 0 || x > 0 {
+		if len(pexp) == 0 {
+			return []float64{o}, []float64{x}
+		}
+		pobs[len(pobs)-1] += o
+		pexp[len(pexp)-1] += x
+	}
+	return pobs, pexp
+}
+
+// testFrequencies returns the frequency table of e, after checking that
+// a test can be carried out on it.
+func testFrequencies(e *Empirical) ([]Frequency, error) {
+	if err := validTestSample(e); err != nil {
+		return nil, err
+	}
+	return e.Frequencies()
+}
+
+// pearsonStatistic returns Pearson's chi-squared statistic Σ (O - E)² / E.
+func pearsonStatistic(obs, exp []float64) float64 {
+	var sum float64
+	for i := range obs {
+		d := obs[i] - exp[i]
+		sum += d * d / exp[i]
+	}
+	return sum
+}
+
+// gStatistic returns the G-test statistic 2 Σ O ln(O / E), where
+// categories with no observations contribute nothing.
+func gStatistic(obs, exp []float64) float64 {
+	var sum float64
+	for i := range obs {
+		if obs[i] > 0 {
+			sum += obs[i] * math.Log(obs[i]/exp[i])
+		}
+	}
+	return 2 * sum
+}
+
+// chiSquaredResult returns the result of a test whose statistic has a
+// chi-squared distribution with df degrees of freedom.
+func chiSquaredResult(stat float64, df int) TestResult {
+	p := special.RegIncGammaUpper(float64(df)/2, stat/2)
+	return TestResult{Statistic: stat, PValue: clampProb(p), DF: df}
+}
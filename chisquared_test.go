@@ -0,0 +1,141 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_ChiSquared_GTest(t *testing.T) {
+	// rolls of a die, with the expected counts of a fair die.
+	e := &Empirical{}
+	for face, count := range []int{5, 8, 9, 8, 10, 20} {
+		for i := 0; i < count; i++ {
+			e.Add(float64(face + 1))
+		}
+	}
+	expected := map[float64]float64{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1}
+
+	res, err := ChiSquared(e, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsPicoEqual(res.Statistic, 13.4) || res.DF != 5 || !floatsNanoEqual(res.PValue, 0.019905220334774376) {
+		t.Fatalf("expected %v, %v, %v\n got %v, %v, %v\n", 13.4, 5, 0.019905220334774376, res.Statistic, res.DF, res.PValue)
+	}
+
+	res, err = GTest(e, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsPicoEqual(res.Statistic, 11.757332492902774) || res.DF != 5 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 11.757332492902774, 5, res.Statistic, res.DF)
+	}
+
+	// with two degrees of freedom the p-value is exp(-x / 2).
+	e = &Empirical{}
+	e.Add(1, 1, 1, 2, 3, 3)
+	res, err = ChiSquared(e, map[float64]float64{1: 2, 2: 2, 3: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DF != 2 || !floatsPicoEqual(res.PValue, math.Exp(-res.Statistic/2)) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 2, math.Exp(-res.Statistic/2), res.DF, res.PValue)
+	}
+
+	// values which are never observed still contribute to the statistic.
+	res, err = ChiSquared(e, map[float64]float64{1: 1, 2: 1, 3: 1, 4: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsPicoEqual(res.Statistic, 10.0/3) || res.DF != 3 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 10.0/3, 3, res.Statistic, res.DF)
+	}
+
+	bad := []map[float64]float64{
+		{1: 1, 2: 1},
+		{1: 1, 2: 1, 3: 0},
+		{1: 1},
+	}
+	for _, exp := range bad {
+		if _, err := ChiSquared(e, exp); err == nil {
+			t.Fatalf("expected error for %v\n", exp)
+		}
+	}
+
+	if _, err := GTest(&Empirical{}, expected); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+}
+
+func Test_ChiSquaredPMF_GTestPMF(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	p := Poisson{Lambda: 4, Rand: rnd}
+	for i := 0; i < 1000; i++ {
+		v, _ := p.Float64()
+		e.Add(v)
+	}
+
+	for _, test := range []func(*Empirical, DiscretePMF) (TestResult, error){ChiSquaredPMF, GTestPMF} {
+		res, err := test(e, Poisson{Lambda: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 0.01 || res.DF < 5 {
+			t.Fatalf("expected p-value above %v\n got %v with %v degrees of freedom\n", 0.01, res.PValue, res.DF)
+		}
+
+		res, err = test(e, Poisson{Lambda: 4.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue > 1e-3 {
+			t.Fatalf("expected p-value below %v\n got %v\n", 1e-3, res.PValue)
+		}
+	}
+
+	e = &Empirical{}
+	e.Add(0, 1.5)
+	if _, err := ChiSquaredPMF(e, Poisson{Lambda: 1}); err == nil {
+		t.Fatal("expected error on non-integer value")
+	}
+
+	// a distant value adds a single category for the gap before it,
+	// rather than one for each integer.
+	e = &Empirical{}
+	e.Add(0, 1, 1, 2, 3, 2e9)
+	for i := 0; i < 20; i++ {
+		e.Add(float64(i % 4))
+	}
+	obs, exp, err := pmfCounts(e, Poisson{Lambda: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tobs, texp float64
+	for i := range obs {
+		tobs, texp = tobs+obs[i], texp+exp[i]
+	}
+	if len(obs) > 6 || tobs != 26 || !floatsPicoEqual(texp, 26) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 26, 26, obs, exp)
+	}
+
+	e = &Empirical{}
+	e.Add(0, 1)
+	if _, err := ChiSquaredPMF(e, Poisson{Lambda: 1}); err == nil {
+		t.Fatal("expected error on too few categories")
+	}
+}
+
+func Test_poolCounts(t *testing.T) {
+	obs := []float64{1, 4, 9, 8, 2, 1}
+	exp := []float64{2, 4, 8, 7, 3, 1}
+	pobs, pexp := poolCounts(obs, exp, 5)
+
+	eobs, eexp := []float64{5, 9, 11}, []float64{6, 8, 11}
+	for i := range eobs {
+		if len(pobs) != len(eobs) || pobs[i] != eobs[i] || pexp[i] != eexp[i] {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", eobs, eexp, pobs, pexp)
+		}
+	}
+}
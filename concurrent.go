@@ -0,0 +1,252 @@
+package godist
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+)
+
+// number of buffered values at which a shard of a ConcurrentEmpirical is
+// merged into the distribution by the goroutine adding to it.
+const concurrentFlushSize = 4096
+
+// A ConcurrentEmpirical is an Empirical distribution which is safe for
+// concurrent use by multiple goroutines.
+//
+// Values added to a ConcurrentEmpirical are buffered in one of several
+// randomly chosen shards, each with its own lock, so that goroutines
+// adding values rarely contend with one another. The shards are merged
+// into a single Empirical distribution lazily, either when a shard's
+// buffer fills, or before any method which reads the distribution. Reads
+// are serialised, because methods such as Float64 cache state derived
+// from the sample.
+//
+// The zero value is an empty distribution with runtime.GOMAXPROCS
+// shards, in the same way as NewConcurrentEmpirical(0).
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Rand must not be changed once the distribution is in use.
+type ConcurrentEmpirical struct {
+	Rand Rand
+
+	once     sync.Once
+	mu       sync.Mutex
+	e        *Empirical
+	sketched bool
+
+	shards []concurrentShard
+}
+
+// concurrentShard buffers values added to a ConcurrentEmpirical.
+type concurrentShard struct {
+	mu      sync.Mutex
+	values  []float64
+	wvalues []float64
+	weights []float64
+}
+
+// NewConcurrentEmpirical returns an empty ConcurrentEmpirical which
+// buffers added values in the provided number of shards. If shards is
+// less than one then the value of runtime.GOMAXPROCS is used.
+func NewConcurrentEmpirical(shards int) *ConcurrentEmpirical {
+	if shards < 1 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	return &ConcurrentEmpirical{e: &Empirical{}, shards: make([]concurrentShard, shards)}
+}
+
+// NewConcurrentSketchedEmpirical returns an empty ConcurrentEmpirical in
+// the same way as NewConcurrentEmpirical, but which summarises the sample
+// using a quantile sketch with accuracy parameter k, as described by
+// NewSketchedEmpirical.
+func NewConcurrentSketchedEmpirical(shards, k int) *ConcurrentEmpirical {
+	c := NewConcurrentEmpirical(shards)
+	c.e = NewSketchedEmpirical(k)
+	c.sketched = true
+	return c
+}
+
+// Add adds one or more values to the empirical sample.
+func (c *ConcurrentEmpirical) Add(values ...float64) {
+	if len(values) == 0 {
+		return
+	}
+
+	s := c.shard()
+	s.mu.Lock()
+	s.values = append(s.values, values...)
+	full := len(s.values)+len(s.wvalues) >= concurrentFlushSize
+	s.mu.Unlock()
+
+	if full {
+		c.mu.Lock()
+		c.drain(s)
+		c.mu.Unlock()
+	}
+}
+
+// AddWeighted adds a value to the empirical sample with the provided
+// weight, in the same way as (*Empirical).AddWeighted.
+//
+// AddWeighted is not supported on sketched distributions.
+func (c *ConcurrentEmpirical) AddWeighted(value, weight float64) error {
+	if c.sketched {
+		msg := "weighted values cannot be added to a sketched distribution."
+		return UnsupportedError{S: msg}
+	}
+
+	if err := validWeight(weight); err != nil {
+		return err
+	} else if weight == 0 {
+		return nil
+	}
+
+	s := c.shard()
+	s.mu.Lock()
+	s.wvalues = append(s.wvalues, value)
+	s.weights = append(s.weights, weight)
+	full := len(s.values)+len(s.wvalues) >= concurrentFlushSize
+	s.mu.Unlock()
+
+	if full {
+		c.mu.Lock()
+		c.drain(s)
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// View calls f with the merged distribution, to which f has exclusive
+// access until it returns. View can be used to call any method of
+// Empirical, or to pass the distribution to a function such as NewKDE,
+// but f must not retain the distribution once it returns.
+func (c *ConcurrentEmpirical) View(f func(e *Empirical)) {
+	c.once.Do(c.init)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.shards {
+		c.drain(&c.shards[i])
+	}
+	f(c.e)
+}
+
+// Mean returns the distribution mean.
+func (c *ConcurrentEmpirical) Mean() (float64, error) {
+	return c.stat((*Empirical).Mean)
+}
+
+// Median returns the distribution median, as described by
+// (*Empirical).Median.
+func (c *ConcurrentEmpirical) Median() (float64, error) {
+	return c.stat((*Empirical).Median)
+}
+
+// Mode returns the distribution mode, as described by
+// (*Empirical).Mode.
+func (c *ConcurrentEmpirical) Mode() (float64, error) {
+	return c.stat((*Empirical).Mode)
+}
+
+// Variance returns the distribution variance.
+func (c *ConcurrentEmpirical) Variance() (float64, error) {
+	return c.stat((*Empirical).Variance)
+}
+
+// Skewness returns the distribution skewness.
+func (c *ConcurrentEmpirical) Skewness() (float64, error) {
+	return c.stat((*Empirical).Skewness)
+}
+
+// ExcessKurtosis returns the distribution excess kurtosis.
+func (c *ConcurrentEmpirical) ExcessKurtosis() (float64, error) {
+	return c.stat((*Empirical).ExcessKurtosis)
+}
+
+// Entropy returns the plug-in estimate of the entropy of the
+// distribution, as described by (*Empirical).Entropy.
+func (c *ConcurrentEmpirical) Entropy() (float64, error) {
+	return c.stat((*Empirical).Entropy)
+}
+
+// Quantile returns the p-quantile of the distribution, as described by
+// (*Empirical).Quantile.
+func (c *ConcurrentEmpirical) Quantile(p float64) (float64, error) {
+	return c.stat(func(e *Empirical) (float64, error) { return e.Quantile(p) })
+}
+
+// Quantiles returns the quantiles of the distribution for each of the
+// provided probabilities.
+func (c *ConcurrentEmpirical) Quantiles(ps ...float64) ([]float64, error) {
+	var qs []float64
+	var err error
+	c.View(func(e *Empirical) { qs, err = e.Quantiles(ps...) })
+	return qs, err
+}
+
+// IQR returns the interquartile range of the distribution.
+func (c *ConcurrentEmpirical) IQR() (float64, error) {
+	return c.stat((*Empirical).IQR)
+}
+
+// Size returns the number of samples in the distribution, or for a
+// weighted distribution the total weight of the samples.
+func (c *ConcurrentEmpirical) Size() float64 {
+	v, _ := c.stat(func(e *Empirical) (float64, error) { return e.Size(), nil })
+	return v
+}
+
+// EffectiveSize returns Kish's effective sample size of the
+// distribution, as described by (*Empirical).EffectiveSize.
+func (c *ConcurrentEmpirical) EffectiveSize() float64 {
+	v, _ := c.stat(func(e *Empirical) (float64, error) { return e.EffectiveSize(), nil })
+	return v
+}
+
+// Float64 returns a randomly sampled value from the distribution.
+func (c *ConcurrentEmpirical) Float64() (float64, error) {
+	return c.stat((*Empirical).Float64)
+}
+
+// stat returns the result of f on the merged distribution.
+func (c *ConcurrentEmpirical) stat(f func(*Empirical) (float64, error)) (float64, error) {
+	var v float64
+	var err error
+	c.View(func(e *Empirical) { v, err = f(e) })
+	return v, err
+}
+
+// shard returns a shard to buffer values in, chosen at random so that
+// goroutines adding values do not share any state until they lock it.
+func (c *ConcurrentEmpirical) shard() *concurrentShard {
+	c.once.Do(c.init)
+	return &c.shards[rand.IntN(len(c.shards))]
+}
+
+// init prepares the distribution for its first use, attaching Rand to the
+// merged distribution before any shard is drained into it. The zero value
+// is given its shards and distribution here.
+func (c *ConcurrentEmpirical) init() {
+	if c.shards == nil {
+		c.shards = make([]concurrentShard, runtime.GOMAXPROCS(0))
+	}
+	if c.e == nil {
+		c.e = &Empirical{}
+	}
+	c.e.Rand = c.Rand
+}
+
+// drain merges the values buffered in s into the distribution. The
+// caller must hold c.mu.
+func (c *ConcurrentEmpirical) drain(s *concurrentShard) {
+	s.mu.Lock()
+	values, wvalues, weights := s.values, s.wvalues, s.weights
+	s.values, s.wvalues, s.weights = nil, nil, nil
+	s.mu.Unlock()
+
+	c.e.Add(values...)
+	for i, v := range wvalues {
+		// weights are validated before they are buffered.
+		c.e.AddWeighted(v, weights[i])
+	}
+}
@@ -0,0 +1,180 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func Test_ConcurrentEmpirical_Imp_Moments(t *testing.T) {
+	var _ Moments = &ConcurrentEmpirical{}
+}
+
+func Test_ConcurrentEmpirical_ZeroValue(t *testing.T) {
+	var c ConcurrentEmpirical
+	if _, err := c.Mean(); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Add(1, 2, 3)
+		}()
+	}
+	wg.Wait()
+
+	if mean, _ := c.Mean(); !floatsPicoEqual(mean, 2) || c.Size() != 12 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 2, 12, mean, c.Size())
+	}
+}
+
+func Test_ConcurrentEmpirical(t *testing.T) {
+	c := NewConcurrentEmpirical(4)
+	if _, err := c.Mean(); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	// concurrent writers and readers, with enough values to fill the
+	// shards several times over.
+	const writers, perWriter = 8, 5000
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				v := float64(w*perWriter + i)
+				if i%10 == 0 {
+					c.AddWeighted(v, 1)
+				} else {
+					c.Add(v)
+				}
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				c.Mean()
+				c.Median()
+				c.Quantile(0.9)
+				c.Size()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	// every value is counted, and the distribution agrees with an
+	// Empirical distribution of the same values.
+	e := &Empirical{}
+	for v := 0; v < writers*perWriter; v++ {
+		e.Add(float64(v))
+	}
+	if c.Size() != e.Size() {
+		t.Fatalf("expected %v\n got %v\n", e.Size(), c.Size())
+	}
+
+	stats := []struct {
+		Name   string
+		Actual func() (float64, error)
+		Exp    func() (float64, error)
+	}{
+		{"mean", c.Mean, e.Mean},
+		{"median", c.Median, e.Median},
+		{"variance", c.Variance, e.Variance},
+		{"skewness", c.Skewness, e.Skewness},
+		{"iqr", c.IQR, e.IQR},
+	}
+	for _, stat := range stats {
+		expected, _ := stat.Exp()
+		actual, err := stat.Actual()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// values are merged in a different order, so the moments differ
+		// by rounding errors.
+		if !floatsEqual(actual, expected, 1e-12*math.Max(1, math.Abs(expected))) {
+			t.Fatalf("expected %v\n got %v for %v\n", expected, actual, stat.Name)
+		}
+	}
+
+	c.View(func(e *Empirical) {
+		if _, err := NewKDE(e, GaussianKernel, SilvermanBandwidth); err == nil {
+			t.Fatal("expected error on weighted distribution")
+		}
+	})
+
+	if err := c.AddWeighted(1, -1); err == nil {
+		t.Fatal("expected error on negative weight")
+	}
+}
+
+func Test_ConcurrentSketchedEmpirical(t *testing.T) {
+	c := NewConcurrentSketchedEmpirical(0, 0)
+	c.Rand = rand.New(rand.NewSource(42))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				c.Add(float64(i))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if c.Size() != 40000 {
+		t.Fatalf("expected %v\n got %v\n", 40000, c.Size())
+	}
+	if m, _ := c.Median(); !floatsEqual(m, 5000, 200) {
+		t.Fatalf("expected %v\n got %v\n", 5000, m)
+	}
+	if v, err := c.Float64(); err != nil || v < 0 || v >= 10000 {
+		t.Fatalf("expected value in [0, 10000)\n got %v, %v\n", v, err)
+	}
+
+	if err := c.AddWeighted(1, 1); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+}
+
+// values merged when a shard fills, before the distribution is first
+// viewed, must be compacted using Rand.
+func Test_ConcurrentSketchedEmpirical_Rand(t *testing.T) {
+	c := NewConcurrentSketchedEmpirical(1, 8)
+	c.Rand = rand.New(rand.NewSource(42))
+	e := NewSketchedEmpirical(8)
+	e.Rand = rand.New(rand.NewSource(42))
+
+	for i := 0; i < 3*concurrentFlushSize+10; i++ {
+		v := float64((i * 7919) % 10007)
+		c.Add(v)
+		e.Add(v)
+	}
+
+	for _, p := range []float64{0.1, 0.5, 0.9} {
+		expected, _ := e.Quantile(p)
+		if actual, _ := c.Quantile(p); actual != expected {
+			t.Fatalf("expected %v\n got %v for %v\n", expected, actual, p)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package godist
+
+import (
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// lfactorial returns the natural logarithm of k!.
+func lfactorial(k int) float64 {
+	return special.LogGamma(float64(k) + 1)
+}
+
+// maxQuantileGuess is the largest guess from which discreteQuantile
+// searches, 2^53, beyond which consecutive integers cannot be
+// distinguished as float64 values.
+const maxQuantileGuess = 1 << 53
+
+// quantileGuess converts x to a guess for discreteQuantile, returning
+// false where x is too large for the quantile to be searched for.
+func quantileGuess(x float64) (int, bool) {
+	if !(x <= maxQuantileGuess) {
+		return 0, false
+	}
+	return int(x), true
+}
+
+// clampInt converts x ≥ 0 to an int, returning math.MaxInt where x is
+// too large to be represented.
+func clampInt(x float64) int {
+	if !(x < math.MaxInt) {
+		return math.MaxInt
+	}
+	return int(x)
+}
+
+// discreteQuantile returns the smallest integer k ≥ 0 where cdf(k) ≥ p,
+// searching outwards from guess, which should be close to the answer
+// (e.g., the distribution mean).
+func discreteQuantile(cdf func(k int) float64, p float64, guess int) int {
+	k := guess
+	if k < 0 {
+		k = 0
+	}
+
+	if cdf(k) >= p {
+		for k > 0 && cdf(k-1) >= p {
+			k--
+		}
+		return k
+	}
+
+	for cdf(k) < p {
+		k++
+	}
+	return k
+}
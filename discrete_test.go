@@ -0,0 +1,68 @@
+package godist
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_discreteQuantile(t *testing.T) {
+	// CDF of a fair six-sided die, labelled 0 to 5.
+	cdf := func(k int) float64 { return math.Min(1, float64(k+1)/6) }
+
+	type Example struct {
+		p     float64
+		guess int
+		out   int
+	}
+
+	examples := []Example{
+		Example{p: 0.5, guess: 0, out: 2},
+		Example{p: 0.5, guess: 5, out: 2},
+		Example{p: 0.5, guess: -3, out: 2},
+		Example{p: 0.1, guess: 4, out: 0},
+		Example{p: 1, guess: 0, out: 5},
+	}
+
+	for _, ex := range examples {
+		if actual := discreteQuantile(cdf, ex.p, ex.guess); actual != ex.out {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_clampInt(t *testing.T) {
+	for _, ex := range []struct {
+		in  float64
+		out int
+	}{{0, 0}, {3, 3}, {1e300, math.MaxInt}, {math.Inf(1), math.MaxInt}} {
+		if actual := clampInt(ex.in); actual != ex.out {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+// checkDiscreteVariates draws n variates and fails if the total variation
+// distance between their observed frequencies and pmf exceeds tol.
+func checkDiscreteVariates(t *testing.T, draw func() (int, error), pmf func(k int) (float64, error), n int, tol float64) {
+	counts := map[int]int{}
+	for i := 0; i < n; i++ {
+		k, err := draw()
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+		counts[k]++
+	}
+
+	var tv, mass float64
+	for k, c := range counts {
+		p, _ := pmf(k)
+		tv += math.Abs(float64(c)/float64(n) - p)
+		mass += p
+	}
+	// mass of values never drawn
+	tv += 1 - mass
+
+	if tv/2 > tol {
+		t.Fatalf("expected total variation below %v\n got %v\n", tol, tv/2)
+	}
+}
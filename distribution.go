@@ -22,3 +22,13 @@ type Distribution interface {
 	// generate a random value according to the probability distribution
 	Float64() (float64, error)
 }
+
+// Moments is the interface implemented by distributions which can also
+// report their third and fourth standardised moments. Callers can check
+// for it with a type assertion on a Distribution.
+type Moments interface {
+	Distribution
+
+	Skewness() (float64, error)
+	ExcessKurtosis() (float64, error)
+}
@@ -0,0 +1,141 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// absolute accuracy requested when numerically integrating the
+	// Kullback-Leibler divergence.
+	klTolerance = 1e-10
+
+	// maximum number of terms summed when calculating the
+	// Kullback-Leibler divergence between discrete distributions.
+	klMaxTerms = 10000000
+)
+
+// continuous is implemented by continuous distributions whose divergence
+// from another distribution can be found by numerical integration.
+type continuous interface {
+	LogPDF(x float64) (float64, error)
+	Quantile(p float64) (float64, error)
+}
+
+// discrete is implemented by discrete distributions on the non-negative
+// integers, whose divergence from another distribution can be found by
+// summation.
+type discrete interface {
+	PMF(k int) (float64, error)
+}
+
+// KLDivergence returns the Kullback-Leibler divergence of q from p in
+// nats, i.e., the expected value under p of ln(p(x) / q(x)).
+//
+// Where p and q are both Beta, Normal or Gamma distributions, the
+// divergence is calculated in closed form. Otherwise, where p and q both
+// have densities and p has a quantile function, the divergence is
+// calculated by numerically integrating
+//
+//	∫ ln p(Q(u)) - ln q(Q(u)) du
+//
+// over [0, 1], where Q is the quantile function of p. Where p and q are
+// both discrete distributions with probability mass functions, the
+// divergence is calculated by summing over the support of p.
+//
+// The divergence is infinite where q is zero somewhere that p is not.
+// KLDivergence returns an UnsupportedError for any other pair of
+// distributions, such as those involving an Empirical distribution.
+func KLDivergence(p, q Distribution) (float64, error) {
+	switch p := p.(type) {
+	case Beta:
+		if q, ok := q.(Beta); ok {
+			return p.KL(q)
+		}
+	case Normal:
+		if q, ok := q.(Normal); ok {
+			return p.KL(q)
+		}
+	case Gamma:
+		if q, ok := q.(Gamma); ok {
+			return p.KL(q)
+		}
+	}
+
+	pc, pok := p.(continuous)
+	qc, qok := q.(continuous)
+	if pok && qok {
+		return klIntegral(pc, qc)
+	}
+
+	pd, pok := p.(discrete)
+	qd, qok := q.(discrete)
+	if pok && qok {
+		return klSum(pd, qd)
+	}
+
+	msg := fmt.Sprintf("KL divergence not supported between %T and %T", p, q)
+	return 0, UnsupportedError{S: msg}
+}
+
+// klIntegral numerically integrates the Kullback-Leibler divergence of q
+// from p, after substituting x = Q(u), where Q is the quantile function
+// of p. The substitution maps the support of p onto [0, 1], where the
+// integral can be evaluated by tanh-sinh quadrature.
+func klIntegral(p, q continuous) (float64, error) {
+	var err error
+	f := func(u float64) float64 {
+		x, qerr := p.Quantile(u)
+		lp, perr := p.LogPDF(x)
+		lq, lerr := q.LogPDF(x)
+		for _, e := range []error{qerr, perr, lerr} {
+			if e != nil && err == nil {
+				err = e
+			}
+		}
+
+		// points where both densities underflow far into the tails, or
+		// where the quantile rounds onto a singularity of p at the edge
+		// of its support, contribute nothing to the integral.
+		d := lp - lq
+		if math.IsNaN(d) || math.IsInf(lp, 1) {
+			return 0
+		}
+		return d
+	}
+
+	kl := integrate(f, 0, 1, klTolerance)
+	if err != nil {
+		return 0, err
+	}
+	return kl, nil
+}
+
+// klSum sums the Kullback-Leibler divergence of q from p, over the
+// non-negative integers until the remaining mass of p is negligible, or
+// the mass function of p underflows beyond the bulk of its support.
+func klSum(p, q discrete) (float64, error) {
+	var kl, mass float64
+	for k := 0; k < klMaxTerms && mass < 1-epsilon; k++ {
+		pk, err := p.PMF(k)
+		if err != nil {
+			return 0, err
+		}
+
+		qk, err := q.PMF(k)
+		if err != nil {
+			return 0, err
+		}
+
+		if pk == 0 && mass > 0 {
+			break
+		} else if pk == 0 {
+			continue
+		} else if qk == 0 {
+			return math.Inf(1), nil
+		}
+		kl += pk * (math.Log(pk) - math.Log(qk))
+		mass += pk
+	}
+	return kl, nil
+}
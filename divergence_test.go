@@ -0,0 +1,79 @@
+package godist
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_KLDivergence(t *testing.T) {
+	type Example struct {
+		p, q Distribution
+		err  error
+		out  float64
+	}
+
+	examples := []Example{
+		Example{p: Beta{Alpha: 1, Beta: 1}, q: Beta{Alpha: 2, Beta: 2}, out: 0.20824053077194504},
+		Example{p: Normal{Mu: 0, Sigma: 1}, q: Normal{Mu: 1, Sigma: 2}, out: 0.4431471805599453},
+		Example{p: Gamma{Shape: 1, Rate: 2}, q: Gamma{Shape: 1, Rate: 1}, out: math.Ln2 - 0.5},
+		// λ₁ ln(λ₁/λ₂) + λ₂ - λ₁ between Poisson distributions.
+		Example{p: Poisson{Lambda: 3}, q: Poisson{Lambda: 5}, out: 0.46752312870202783},
+		Example{p: Binomial{N: 4, P: 1}, q: Binomial{N: 4, P: 0.5}, out: math.Log(16)},
+		Example{p: Binomial{N: 4, P: 0.5}, q: Binomial{N: 4, P: 1}, out: math.Inf(1)},
+		Example{
+			p:   Beta{Alpha: 1, Beta: 1},
+			q:   Poisson{Lambda: 1},
+			err: UnsupportedError{S: "KL divergence not supported between godist.Beta and godist.Poisson"},
+		},
+		Example{
+			p:   &Empirical{},
+			q:   Normal{Mu: 0, Sigma: 1},
+			err: UnsupportedError{S: "KL divergence not supported between *godist.Empirical and godist.Normal"},
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := KLDivergence(ex.p, ex.q)
+		if err != ex.err {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) && actual != ex.out {
+			t.Fatalf("expected %v\n got %v for %#v\n", ex.out, actual, ex.p)
+		}
+	}
+}
+
+func Test_KLDivergence_Integral(t *testing.T) {
+	type Example struct {
+		p, q continuous
+	}
+
+	examples := []Example{
+		Example{p: Beta{Alpha: 2, Beta: 5}, q: Beta{Alpha: 3, Beta: 1.5}},
+		Example{p: Beta{Alpha: 0.5, Beta: 0.5}, q: Beta{Alpha: 1, Beta: 1}},
+		Example{p: Normal{Mu: 0, Sigma: 1}, q: Normal{Mu: 1, Sigma: 2}},
+		Example{p: Normal{Mu: 2, Sigma: 0.5}, q: Normal{Mu: -1, Sigma: 3}},
+		Example{p: Gamma{Shape: 3, Rate: 2}, q: Gamma{Shape: 1.5, Rate: 0.5}},
+		Example{p: Gamma{Shape: 0.7, Rate: 1}, q: Gamma{Shape: 1, Rate: 1}},
+	}
+
+	for _, ex := range examples {
+		// dispatch directly to the closed form via KLDivergence.
+		expected, err := KLDivergence(ex.p.(Distribution), ex.q.(Distribution))
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		actual, err := klIntegral(ex.p, ex.q)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		// the quantile function loses precision where the density of p
+		// is unbounded, which limits the accuracy of the integral.
+		if !floatsEqual(actual, expected, 1e-7) {
+			t.Fatalf("expected %v\n got %v for %#v\n", expected, actual, ex.p)
+		}
+	}
+}
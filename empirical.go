@@ -1,171 +1,572 @@
 package godist
 
 import (
-	"math/rand"
-
-	"sort"
+	"fmt"
+	"math"
 )
 
+// DefaultSketchK is the accuracy parameter used by sketched Empirical
+// distributions when none is provided.
+const DefaultSketchK = 200
+
 // An Empirical distribution in the context of the godist package is
 // essentially just a sample of discrete values.
 //
-// All values added to an Empirical Distribution remain in memory, and
-// while some efficiencies have been made around memoising certain
-// values, in general calls to Median and Mode currently involve
-// re-sorting the entire sample in the Empirical distribution.
+// All values added to an Empirical Distribution remain in memory, held
+// in an order-statistic tree with a node for each distinct value, which
+// counts its occurrences. The tree is kept ordered as values are added
+// and removed, so that Median, Quantile and Mode take O(log n) time for n
+// distinct values, rather than requiring the sample to be sorted.
+//
+// Alternatively, a bounded-memory Empirical distribution can be created
+// with NewSketchedEmpirical, which summarises the sample using a
+// quantile sketch rather than retaining every value.
+//
+// Values may also be added with a weight using AddWeighted, such as the
+// importance weights of values drawn by importance sampling, in which
+// case the distribution is a weighted sample.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
 type Empirical struct {
-	sample   []float64
-	mean     float64
-	median   float64
-	mode     float64
-	variance float64
-	n        float64
-	medStale bool
-	modStale bool
+	Rand Rand
+	moments
+
+	// tree holds the values in the sample. It is unweighted unless a
+	// value has been added with AddWeighted, in which case it also holds
+	// the weight of each value.
+	tree ostree
+
+	// sorted is the sample in increasing order, and alias samples the
+	// distinct values in aliasValues of a weighted sample. Both are built
+	// when needed, and discarded when the sample changes.
+	sorted      []float64
+	alias       *aliasTable
+	aliasValues []float64
+
+	sketch *kllSketch
+}
+
+// NewSketchedEmpirical returns an Empirical distribution which uses a
+// KLL quantile sketch with accuracy parameter k, rather than retaining
+// every value in memory. If k is less than 8 then DefaultSketchK is used.
+//
+// A sketched distribution retains roughly 3k values regardless of how
+// many are added. Mean and Variance remain exact, while Median, Quantile
+// and Float64 are approximate: the rank of a returned quantile differs
+// from the requested rank by O(1/k). With the default k = 200, rank
+// errors are typically below 1% of the sample size, and rarely exceed
+// 2%. Mode is not supported.
+func NewSketchedEmpirical(k int) *Empirical {
+	if k < 8 {
+		k = DefaultSketchK
+	}
+	return &Empirical{sketch: newKLLSketch(k)}
 }
 
 // Add adds one or more values to the empirical sample.
 //
-// Add carries out some operations to improve the efficiency of other
-// method calls, which is the main reason why the underlying sample
-// data-structure is not exported.
+// Add inserts each value into the order-statistic tree holding the
+// sample, in O(log n) time, which is the main reason why the underlying
+// sample data-structure is not exported.
 func (e *Empirical) Add(values ...float64) {
 	if len(values) == 0 {
 		return
 	}
-	e.sample = append(e.sample, values...)
 
-	// update moments
+	if e.sketch == nil {
+		e.changed()
+	}
+
 	for _, v := range values {
-		if e.n == 0 {
-			e.n = 1
-			e.mean, e.median, e.mode = values[0], values[0], values[0]
-			e.medStale, e.modStale = false, false
-			continue
+		if e.sketch != nil {
+			e.sketch.add(e.Rand, v)
+		} else {
+			e.tree.insert(v, 1)
 		}
 
-		// update running mean and variance
-		e.n++
-		curmean := e.mean
-		e.mean += (v - e.mean) / e.n
-		e.variance += (v - curmean) * (v - e.mean)
+		// update running moments
+		e.moments.add(v)
+	}
+}
+
+// Merge adds all the values in other to the distribution, combining
+// their moments using the parallel algorithm described by Chan et al. in
+// "Updating Formulae and a Pairwise Algorithm for Computing Sample
+// Variances" (1979), and its extension to higher moments, rather than
+// adding each value in turn. The weights of any weighted values are
+// retained.
+//
+// Merge is supported where both distributions are sketched, or where
+// neither is.
+func (e *Empirical) Merge(other *Empirical) error {
+	if (e.sketch == nil) != (other.sketch == nil) {
+		msg := "merge is not supported between sketched and unsketched distributions."
+		return UnsupportedError{S: msg}
+	}
+
+	if other.n == 0 {
+		return nil
+	}
+
+	if e.sketch != nil {
+		e.moments.merge(other.moments)
+		e.sketch.merge(e.Rand, other.sketch)
+		return nil
+	}
+
+	if other.tree.weighted {
+		e.tree.weigh()
+	}
 
-		// check if we need to make the current median/mods values
-		// stale.
-		if v != e.median {
-			e.medStale = true
+	// collect the values of other before inserting them, in case other
+	// is e.
+	var values, weights []float64
+	other.tree.each(func(n *osnode) {
+		for i := 0; i < n.count; i++ {
+			values = append(values, n.key)
+			weights = append(weights, n.occurrence(i))
 		}
+	})
+	for i, v := range values {
+		e.tree.insert(v, weights[i])
+	}
+
+	e.changed()
+	e.moments.merge(other.moments)
+	return nil
+}
+
+// Remove removes one occurrence of each of the provided values from the
+// distribution, downdating its moments rather than recalculating them.
+// For a weighted distribution, the earliest remaining occurrence of each
+// value is removed along with its weight.
+//
+// If any of the values does not occur in the distribution, often enough
+// to be removed, then an error is returned and the distribution is left
+// unchanged.
+//
+// Remove is not supported on sketched distributions, which do not retain
+// every value.
+func (e *Empirical) Remove(values ...float64) error {
+	if e.sketch != nil {
+		msg := "values cannot be removed from a sketched distribution."
+		return UnsupportedError{S: msg}
+	}
 
-		if v != e.mode {
-			e.modStale = true
+	if len(values) == 0 {
+		return nil
+	}
+
+	pending := make(map[float64]int, len(values))
+	for _, v := range values {
+		pending[v]++
+	}
+
+	for v, c := range pending {
+		if e.tree.count(v) < c {
+			msg := fmt.Sprintf("value %v cannot be removed as it is not in the distribution.", v)
+			return InvalidDistributionError{S: msg}
 		}
 	}
 
+	// the moments of the removed values.
+	var removed moments
+	for _, v := range values {
+		w, _ := e.tree.remove(v)
+		removed.merge(moments{n: w, mean: v})
+	}
+
+	e.changed()
+	if e.tree.len() == 0 {
+		e.tree, e.moments = ostree{}, moments{}
+		return nil
+	}
+	e.moments.remove(removed)
+	return nil
 }
 
 // Mean returns the distribution mean.
 func (e *Empirical) Mean() (float64, error) {
-	if len(e.sample) == 0 {
+	if e.n == 0 {
 		msg := "mean cannot be calculated on empty distribution."
 		return 0.0, InvalidDistributionError{S: msg}
 	}
 	return e.mean, nil
 }
 
-// Median calculates the distribution median.
-//
-// Median returns a memoised median if either: (1) the distribution has
-// not been updated since the last call to Median, or (2) all values
-// added to the distribution since the last call are equal to the median
-// of the distribution.
+// Median calculates the distribution median, in O(log n) time for n
+// distinct values.
 //
 // In the case that the distribution sample size is even, the mean of
-// the two middle values is returned.
+// the two middle values is returned. For a weighted distribution the
+// weighted median is returned, which is calculated in the same way as
+// Quantile.
 func (e *Empirical) Median() (float64, error) {
-	if len(e.sample) == 0 {
+	if e.n == 0 {
 		msg := "median cannot be calculated on empty distribution."
 		return 0.0, InvalidDistributionError{S: msg}
 	}
 
-	if !e.medStale {
-		// no new values, or only values equal to current median added
-		return e.median, nil
+	if e.sketch != nil {
+		return e.sketch.quantile(0.5), nil
+	}
+
+	if e.tree.weighted {
+		return e.tree.weightedQuantile(0.5), nil
 	}
 
-	e.medStale = false
-	// sort sample to find median value
-	sort.Float64s(e.sample)
-	mid := int64(e.n) / 2
-	if int64(e.n)%2 == 1 {
-		e.median = e.sample[mid]
-		return e.median, nil
+	size := e.tree.len()
+	mid := size / 2
+	if size%2 == 1 {
+		return e.tree.at(mid), nil
 	}
-	e.median = (e.sample[mid-1] + e.sample[mid]) / 2.0
-	return e.median, nil
+	return (e.tree.at(mid-1) + e.tree.at(mid)) / 2.0, nil
 }
 
-// Mode calculates the distribution mode.
-//
-// Mode returns a memoised if either: (1) the distribution has not been
-// updated since the last call to Mode, or (2) all values added to the
-// distribution since the last call are equal to the mode of the
-// distribution.
+// Mode calculates the distribution mode in constant time, as every node
+// of the tree holding the sample tracks the most frequent value beneath
+// it.
 //
 // In the case that the distribution is multi-modal, the smallest mode
-// is returned.
+// is returned. For a weighted distribution the mode is the value with
+// the greatest total weight.
 func (e *Empirical) Mode() (float64, error) {
-	if len(e.sample) == 0 {
+	if e.n == 0 {
 		msg := "mode cannot be calculated on empty distribution."
 		return 0.0, InvalidDistributionError{S: msg}
 	}
 
-	if !e.modStale {
-		// no new values, or only values equal to current median added
-		return e.mode, nil
+	if e.sketch != nil {
+		msg := "mode cannot be calculated on a sketched distribution."
+		return 0.0, UnsupportedError{S: msg}
+	}
+
+	return e.tree.mode(), nil
+}
+
+// A Frequency is a distinct value in an Empirical sample, and the number
+// of times it occurs.
+type Frequency struct {
+	Value float64
+	Count int
+}
+
+// Frequencies returns the frequency table of the distribution, i.e., each
+// distinct value in the sample with the number of times it occurs, in
+// increasing order of value.
+//
+// Frequencies is not supported on sketched distributions, which do not
+// retain the frequency of every value, or on weighted distributions.
+func (e *Empirical) Frequencies() ([]Frequency, error) {
+	if e.n == 0 {
+		msg := "frequencies cannot be calculated on empty distribution."
+		return nil, InvalidDistributionError{S: msg}
 	}
 
-	e.modStale = false
-	sort.Float64s(e.sample)
+	if e.sketch != nil {
+		msg := "frequencies cannot be calculated on a sketched distribution."
+		return nil, UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "frequencies cannot be calculated on a weighted distribution."
+		return nil, UnsupportedError{S: msg}
+	}
 
-	modei, maxc := 0, 1
-	for i := 0; i < int(e.n); i++ {
-		count := 1
-		for j := i + 1; j < int(e.n); j++ {
-			if e.sample[j] != e.sample[i] {
-				break
-			}
-			count++
+	var freqs []Frequency
+	e.tree.each(func(n *osnode) {
+		freqs = append(freqs, Frequency{Value: n.key, Count: n.count})
+	})
+	return freqs, nil
+}
+
+// Entropy returns the plug-in estimate of the entropy of the
+// distribution in nats, i.e., the Shannon entropy of the relative
+// frequencies of the distinct values in the sample. For a weighted
+// distribution the relative frequency of a value is its share of the
+// total weight.
+//
+// Entropy is not supported on sketched distributions, which do not
+// retain the frequency of every value.
+func (e *Empirical) Entropy() (float64, error) {
+	if e.n == 0 {
+		msg := "entropy cannot be calculated on empty distribution."
+		return 0.0, InvalidDistributionError{S: msg}
+	}
+
+	if e.sketch != nil {
+		msg := "entropy cannot be calculated on a sketched distribution."
+		return 0.0, UnsupportedError{S: msg}
+	}
+
+	// H = -Σ (c/n) ln(c/n) = ln(n) - Σ c ln(c) / n
+	var sum float64
+	e.tree.each(func(n *osnode) {
+		sum += n.weight * math.Log(n.weight)
+	})
+	return math.Log(e.n) - sum/e.n, nil
+}
+
+// Quantile returns the p-quantile of the distribution.
+//
+// Quantile linearly interpolates between the order statistics of the
+// sample, which corresponds to type 7 of QuantileType and the default
+// method used by R, and agrees with Median where p = 0.5.
+//
+// For sketched distributions the returned value is approximate, and is
+// always one of the values added to the distribution.
+//
+// For weighted distributions the weighted quantile is returned. Each
+// value is placed at the midpoint of its weight within the cumulative
+// weight of the sorted sample, rescaled so that the smallest and largest
+// values lie at p = 0 and p = 1, and the quantile linearly interpolates
+// between them. Where every weight is equal this agrees with type 7.
+func (e *Empirical) Quantile(p float64) (float64, error) {
+	if e.sketch != nil {
+		if err := e.validQuantile(p); err != nil {
+			return 0.0, err
+		}
+		return e.sketch.quantile(p), nil
+	} else if e.tree.weighted {
+		if err := e.validQuantile(p); err != nil {
+			return 0.0, err
 		}
+		return e.tree.weightedQuantile(p), nil
+	}
+	return e.QuantileType(p, 7)
+}
+
+// QuantileType returns the p-quantile of the distribution, calculated
+// using one of the nine sample quantile definitions described by
+// Hyndman and Fan in "Sample Quantiles in Statistical Packages" (1996),
+// which are numbered 1 to 9 in the same way as R's quantile function.
+//
+// Types 1 to 3 are discontinuous in p, and always return a value from
+// the sample. Types 4 to 9 interpolate between the order statistics.
+//
+// QuantileType is not supported for sketched or weighted distributions.
+func (e *Empirical) QuantileType(p float64, t int) (float64, error) {
+	if err := e.validQuantile(p); err != nil {
+		return 0.0, err
+	}
+
+	if t < 1 || t > 9 {
+		msg := fmt.Sprintf("quantile type %v not supported.", t)
+		return 0.0, UnsupportedError{S: msg}
+	} else if e.sketch != nil {
+		msg := "quantile types cannot be calculated on a sketched distribution."
+		return 0.0, UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "quantile types cannot be calculated on a weighted distribution."
+		return 0.0, UnsupportedError{S: msg}
+	}
+
+	return e.tree.quantile(p, t), nil
+}
 
-		if count > maxc {
-			modei, maxc = i, count
+// Quantiles returns the quantiles of the distribution for each of the
+// provided probabilities, calculated in the same way as Quantile.
+func (e *Empirical) Quantiles(ps ...float64) ([]float64, error) {
+	qs := make([]float64, len(ps))
+	for i, p := range ps {
+		q, err := e.Quantile(p)
+		if err != nil {
+			return nil, err
 		}
+		qs[i] = q
 	}
-	e.mode = e.sample[modei]
-	return e.mode, nil
+	return qs, nil
+}
+
+// IQR returns the interquartile range of the distribution, i.e., the
+// difference between the 0.75 and 0.25 quantiles calculated using
+// Quantile.
+func (e *Empirical) IQR() (float64, error) {
+	qs, err := e.Quantiles(0.25, 0.75)
+	if err != nil {
+		return 0.0, err
+	}
+	return qs[1] - qs[0], nil
+}
+
+// validQuantile returns an error if the p-quantile cannot be calculated.
+func (e *Empirical) validQuantile(p float64) error {
+	if e.n == 0 {
+		msg := "quantile cannot be calculated on empty distribution."
+		return InvalidDistributionError{S: msg}
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return UnsupportedError{S: msg}
+	}
+	return nil
+}
+
+// hyndmanFan returns the p-quantile of the sorted sample x, using sample
+// quantile definition t, following the implementation of R's quantile
+// function.
+func hyndmanFan(x []float64, p float64, t int) float64 {
+	return orderQuantile(len(x), func(i int) float64 { return x[i] }, p, t)
+}
+
+// orderQuantile returns the p-quantile of a sample of size n, in the same
+// way as hyndmanFan, where x returns the i-th smallest value in the
+// sample, counting from zero.
+func orderQuantile(size int, x func(i int) float64, p float64, t int) float64 {
+	n := float64(size)
+	// tolerance for rounding errors in the position of the quantile.
+	fuzz := 4 * epsilon
+
+	// at returns the 1-based order statistic j, clamped to the sample.
+	at := func(j float64) float64 {
+		if j < 1 {
+			return x(0)
+		} else if j > n {
+			return x(size - 1)
+		}
+		return x(int(j) - 1)
+	}
+
+	var j, h float64
+	if t <= 3 {
+		m := 0.0
+		if t == 3 {
+			m = -0.5
+		}
+		nppm := m + n*p
+		j = math.Floor(nppm + fuzz)
+
+		switch {
+		case t == 1 && nppm > j:
+			h = 1
+		case t == 2 && nppm > j:
+			h = 1
+		case t == 2:
+			h = 0.5
+		case t == 3 && (nppm != j || math.Mod(j, 2) == 1):
+			h = 1
+		}
+	} else {
+		// plotting positions (a, b) for each continuous type.
+		ab := [...][2]float64{
+			4: {0, 1}, 5: {0.5, 0.5}, 6: {0, 0},
+			7: {1, 1}, 8: {1.0 / 3, 1.0 / 3}, 9: {3.0 / 8, 3.0 / 8},
+		}
+		a, b := ab[t][0], ab[t][1]
+		nppm := a + p*(n+1-a-b)
+		j = math.Floor(nppm + fuzz)
+		h = nppm - j
+		if math.Abs(h) < fuzz {
+			h = 0
+		}
+	}
+
+	if h == 0 {
+		return at(j)
+	} else if h == 1 {
+		return at(j + 1)
+	}
+	return (1-h)*at(j) + h*at(j+1)
 }
 
 // Variance returns the distribution variance.
 func (e *Empirical) Variance() (float64, error) {
-	if len(e.sample) == 0 {
+	if e.n == 0 {
 		msg := "variance cannot be calculated on empty distribution."
 		return 0.0, InvalidDistributionError{S: msg}
 	}
-	return e.variance / e.n, nil
+	return e.m2 / e.n, nil
 }
 
-// Size returns the number of samples in the distribution.
+// Skewness returns the distribution skewness, i.e., the third
+// standardised moment of the sample.
+//
+// The skewness is calculated from running moments which are updated by
+// Add, according to the algorithm described by Terriberry in "Computing
+// Higher-Order Moments Online" (2007).
+func (e *Empirical) Skewness() (float64, error) {
+	if e.n == 0 {
+		msg := "skewness cannot be calculated on empty distribution."
+		return 0.0, InvalidDistributionError{S: msg}
+	} else if e.m2 == 0 {
+		msg := "skewness cannot be calculated on distribution with zero variance."
+		return 0.0, UnsupportedError{S: msg}
+	}
+	return math.Sqrt(e.n) * e.m3 / math.Pow(e.m2, 1.5), nil
+}
+
+// ExcessKurtosis returns the distribution excess kurtosis, i.e., the
+// fourth standardised moment of the sample, minus three.
+//
+// The kurtosis is calculated from running moments which are updated by
+// Add, in the same way as Skewness.
+func (e *Empirical) ExcessKurtosis() (float64, error) {
+	if e.n == 0 {
+		msg := "kurtosis cannot be calculated on empty distribution."
+		return 0.0, InvalidDistributionError{S: msg}
+	} else if e.m2 == 0 {
+		msg := "kurtosis cannot be calculated on distribution with zero variance."
+		return 0.0, UnsupportedError{S: msg}
+	}
+	return e.n*e.m4/(e.m2*e.m2) - 3, nil
+}
+
+// Size returns the number of samples in the distribution, or for a
+// weighted distribution the total weight of the samples.
 func (e *Empirical) Size() float64 {
 	return e.n
 }
 
 // Float64 returns a randomly sampled value from the Empirical
 // distribution.
+//
+// Values in a weighted distribution are drawn with probability
+// proportional to their weight, using an alias table over the distinct
+// values which is built on the first call after the distribution is
+// updated.
 func (e *Empirical) Float64() (float64, error) {
-	if len(e.sample) == 0 {
+	if e.n == 0 {
 		msg := "cannot draw a random value on an empty distribution."
 		return 0.0, InvalidDistributionError{S: msg}
 	}
-	i := rand.Intn(len(e.sample))
-	return e.sample[i], nil
+
+	if e.sketch != nil {
+		return e.sketch.sample(e.Rand), nil
+	}
+
+	if e.tree.weighted {
+		if e.alias == nil {
+			var weights []float64
+			e.tree.each(func(n *osnode) {
+				e.aliasValues = append(e.aliasValues, n.key)
+				weights = append(weights, n.weight)
+			})
+			e.alias = newAliasTable(weights)
+		}
+		return e.aliasValues[e.alias.sample(e.Rand)], nil
+	}
+
+	i := randIntn(e.Rand, e.tree.len())
+	return e.tree.at(i), nil
+}
+
+// sortedSample returns the values in the sample in increasing order,
+// which must not be modified.
+func (e *Empirical) sortedSample() []float64 {
+	if e.sorted == nil {
+		e.sorted = make([]float64, 0, e.tree.len())
+		e.tree.each(func(n *osnode) {
+			for i := 0; i < n.count; i++ {
+				e.sorted = append(e.sorted, n.key)
+			}
+		})
+	}
+	return e.sorted
+}
+
+// changed discards the sorted sample and alias table after the sample
+// changes.
+func (e *Empirical) changed() {
+	e.sorted = nil
+	e.alias, e.aliasValues = nil, nil
 }
@@ -1,11 +1,16 @@
 package godist
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
 	"testing"
 )
 
 func Test_Empirical_Imp_Distribution(t *testing.T) {
 	var _ Distribution = &Empirical{}
+	var _ Moments = &Empirical{}
 }
 
 func Test_Empirical_Mean(t *testing.T) {
@@ -146,3 +151,519 @@ func Test_Empirical_Variance(t *testing.T) {
 		}
 	}
 }
+
+func Test_Empirical_Skewness_Kurtosis(t *testing.T) {
+	type Example struct {
+		in             []float64
+		err            error
+		skew, kurtosis float64
+	}
+
+	examples := []Example{
+		Example{in: []float64{1, 2, 3, 4}, skew: 0, kurtosis: -1.36},
+		Example{in: []float64{1.1, 1.1, 4.1}, skew: 0.7071067811865472, kurtosis: -1.5},
+		Example{in: []float64{1, 2, 3, 4, 10}, skew: 1.1384199576606167, kurtosis: -0.212},
+		Example{
+			in:  []float64{1.1, 1.1},
+			err: UnsupportedError{S: "skewness cannot be calculated on distribution with zero variance."},
+		},
+		Example{
+			in:  nil,
+			err: InvalidDistributionError{S: "skewness cannot be calculated on empty distribution."},
+		},
+	}
+
+	for _, ex := range examples {
+		em := Empirical{}
+		em.Add(ex.in...)
+		skew, err := em.Skewness()
+		if err != ex.err {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		kurtosis, _ := em.ExcessKurtosis()
+		if !floatsPicoEqual(skew, ex.skew) || !floatsPicoEqual(kurtosis, ex.kurtosis) {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", ex.skew, ex.kurtosis, skew, kurtosis)
+		}
+	}
+}
+
+func Test_Empirical_Entropy(t *testing.T) {
+	type Example struct {
+		in  []float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: []float64{4}, out: 0},
+		Example{in: []float64{2, 1, 1, 3}, out: 1.0397207708399179},
+		Example{in: []float64{1, 2, 3, 4}, out: math.Log(4)},
+		Example{
+			in:  nil,
+			err: InvalidDistributionError{S: "entropy cannot be calculated on empty distribution."},
+		},
+	}
+
+	for _, ex := range examples {
+		em := Empirical{}
+		em.Add(ex.in...)
+		actual, err := em.Entropy()
+		if err != ex.err {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2)
+	if _, err := sk.Entropy(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Empirical_Frequencies(t *testing.T) {
+	type Example struct {
+		in  []float64
+		err error
+		out []Frequency
+	}
+
+	examples := []Example{
+		Example{in: []float64{4}, out: []Frequency{{Value: 4, Count: 1}}},
+		Example{
+			in:  []float64{2, 1, 1, 3, 1, 2},
+			out: []Frequency{{Value: 1, Count: 3}, {Value: 2, Count: 2}, {Value: 3, Count: 1}},
+		},
+		Example{
+			in:  nil,
+			err: InvalidDistributionError{S: "frequencies cannot be calculated on empty distribution."},
+		},
+	}
+
+	for _, ex := range examples {
+		em := Empirical{}
+		em.Add(ex.in...)
+		actual, err := em.Frequencies()
+		if err != ex.err {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if fmt.Sprint(actual) != fmt.Sprint(ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2)
+	if _, err := sk.Frequencies(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Empirical_Float64_Rand(t *testing.T) {
+	dist := Empirical{Rand: rand.New(rand.NewSource(7))}
+	dist.Add(1, 2, 3, 4, 5)
+
+	for _, exp := range []float64{5, 2, 2, 5, 4, 1} {
+		actual, err := dist.Float64()
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if actual != exp {
+			t.Fatalf("expected %v\n got %v\n", exp, actual)
+		}
+	}
+}
+
+func Test_Empirical_Quantile(t *testing.T) {
+	type Example struct {
+		in  []float64
+		p   float64
+		err error
+		out float64
+	}
+
+	examples := []Example{
+		Example{in: []float64{1.1}, p: 0.3, out: 1.1},
+		Example{in: []float64{4, 1, 3, 2}, p: 0, out: 1},
+		Example{in: []float64{4, 1, 3, 2}, p: 1, out: 4},
+		Example{in: []float64{4, 1, 3, 2}, p: 0.5, out: 2.5},
+		Example{in: []float64{4, 1, 3, 2}, p: 0.9, out: 3.7},
+		Example{in: []float64{10, 20, 30, 40, 50}, p: 0.1, out: 14},
+		Example{
+			in:  nil,
+			p:   0.5,
+			err: InvalidDistributionError{S: "quantile cannot be calculated on empty distribution."},
+		},
+		Example{
+			in:  []float64{1},
+			p:   -0.1,
+			err: UnsupportedError{S: "Quantile not supported for p = -0.1"},
+		},
+	}
+
+	for _, ex := range examples {
+		dist := Empirical{}
+		dist.Add(ex.in...)
+		actual, err := dist.Quantile(ex.p)
+		if err != ex.err {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Empirical_QuantileType(t *testing.T) {
+	type Example struct {
+		in  []float64
+		p   float64
+		out [9]float64
+	}
+
+	seq := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	sample := []float64{2.5, 7.1, 3.3, 9.8, 4.4, 1.2, 8.0}
+
+	// expected values as calculated by R's quantile function.
+	examples := []Example{
+		Example{in: seq, p: 0.1, out: [9]float64{1, 1.5, 1, 1, 1.5, 1.1, 1.9, 1.3666666666666665, 1.4}},
+		Example{in: seq, p: 0.25, out: [9]float64{3, 3, 2, 2.5, 3, 2.75, 3.25, 2.9166666666666665, 2.9375}},
+		Example{in: seq, p: 0.5, out: [9]float64{5, 5.5, 5, 5, 5.5, 5.5, 5.5, 5.5, 5.5}},
+		Example{in: seq, p: 0, out: [9]float64{1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		Example{in: seq, p: 1, out: [9]float64{10, 10, 10, 10, 10, 10, 10, 10, 10}},
+		Example{in: sample, p: 0.33, out: [9]float64{3.3, 3.3, 2.5, 2.748, 3.148, 3.012, 3.284, 3.102666666666667, 3.114}},
+		Example{in: sample, p: 0.9, out: [9]float64{9.8, 9.8, 8.0, 8.54, 9.44, 9.8, 8.72, 9.68, 9.62}},
+	}
+
+	for _, ex := range examples {
+		dist := Empirical{}
+		dist.Add(ex.in...)
+		for i, exp := range ex.out {
+			actual, err := dist.QuantileType(ex.p, i+1)
+			if err != nil {
+				t.Fatalf("expected no error\n got %v\n", err)
+			}
+
+			if !floatsPicoEqual(actual, exp) {
+				t.Fatalf("[Type %v] expected %v\n got %v for p = %v\n", i+1, exp, actual, ex.p)
+			}
+		}
+	}
+
+	dist := Empirical{}
+	dist.Add(seq...)
+	for _, typ := range []int{0, 10} {
+		exp := UnsupportedError{S: fmt.Sprintf("quantile type %v not supported.", typ)}
+		if _, err := dist.QuantileType(0.5, typ); err != exp {
+			t.Fatalf("expected %v\n got %v\n", exp, err)
+		}
+	}
+}
+
+func Test_Empirical_Quantiles(t *testing.T) {
+	dist := Empirical{}
+	dist.Add(2.5, 7.1, 3.3, 9.8, 4.4, 1.2, 8.0)
+
+	actual, err := dist.Quantiles(0.9, 0.5, 0.99)
+	if err != nil {
+		t.Fatalf("expected no error\n got %v\n", err)
+	}
+
+	expected := []float64{8.72, 4.4, 9.692}
+	for i := range expected {
+		if !floatsPicoEqual(actual[i], expected[i]) {
+			t.Fatalf("expected %v\n got %v\n", expected, actual)
+		}
+	}
+
+	if _, err := dist.Quantiles(0.5, 2); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+
+	iqr, _ := dist.IQR()
+	if !floatsPicoEqual(iqr, 4.65) {
+		t.Fatalf("expected %v\n got %v\n", 4.65, iqr)
+	}
+
+	empty := Empirical{}
+	if _, err := empty.IQR(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+// sketched quantiles must be within the documented rank error of the
+// exact quantiles.
+func Test_Empirical_Sketched(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	exact, sketched := &Empirical{}, NewSketchedEmpirical(0)
+	sketched.Rand = rnd
+
+	n := 200000
+	for i := 0; i < n; i++ {
+		v, _ := Normal{Mu: 5, Sigma: 2, Rand: rnd}.Float64()
+		exact.Add(v)
+		sketched.Add(v)
+	}
+
+	if sketched.Size() != exact.Size() || sketched.tree.len() != 0 {
+		t.Fatalf("expected %v values with none retained\n got %v, %v\n", exact.Size(), sketched.Size(), sketched.tree.len())
+	}
+
+	em, _ := exact.Mean()
+	sm, _ := sketched.Mean()
+	ev, _ := exact.Variance()
+	sv, _ := sketched.Variance()
+	if !floatsPicoEqual(em, sm) || !floatsNanoEqual(ev, sv) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", em, ev, sm, sv)
+	}
+
+	for _, p := range []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99} {
+		actual, _ := sketched.Quantile(p)
+		rank := sort.SearchFloat64s(exact.sortedSample(), actual)
+		if err := math.Abs(float64(rank)/float64(n) - p); err > 0.02 {
+			t.Fatalf("expected rank error below 0.02\n got %v for p = %v\n", err, p)
+		}
+	}
+
+	med, _ := sketched.Median()
+	q, _ := sketched.Quantile(0.5)
+	if med != q {
+		t.Fatalf("expected %v\n got %v\n", q, med)
+	}
+
+	if _, err := sketched.Mode(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+
+	// draws should follow the same distribution as the sample.
+	draws := &Empirical{}
+	for i := 0; i < 10000; i++ {
+		v, _ := sketched.Float64()
+		draws.Add(v)
+	}
+	dm, _ := draws.Mean()
+	if !floatsDeciEqual(dm, em) {
+		t.Fatalf("expected %v\n got %v\n", em, dm)
+	}
+}
+
+func Test_Empirical_Merge_Sketched(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a, b, all := NewSketchedEmpirical(100), NewSketchedEmpirical(100), &Empirical{}
+	a.Rand, b.Rand = rnd, rnd
+	for i := 0; i < 20000; i++ {
+		v, w := rnd.Float64(), 1+rnd.Float64()
+		a.Add(v)
+		b.Add(w)
+		all.Add(v, w)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("expected no error\n got %v\n", err)
+	}
+
+	am, _ := a.Mean()
+	em, _ := all.Mean()
+	av, _ := a.Variance()
+	ev, _ := all.Variance()
+	if !floatsPicoEqual(am, em) || !floatsNanoEqual(av, ev) || a.Size() != all.Size() {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", em, ev, am, av)
+	}
+
+	if med, _ := a.Median(); !floatsDeciEqual(med, 1) {
+		t.Fatalf("expected %v\n got %v\n", 1, med)
+	}
+
+	if err := a.Merge(all); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Empirical_Merge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a, b, all := &Empirical{}, &Empirical{}, &Empirical{}
+	for i := 0; i < 1000; i++ {
+		v, w := rnd.ExpFloat64(), 3+rnd.NormFloat64()
+		a.Add(v)
+		b.Add(w)
+		all.Add(v, w)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := []func(*Empirical) (float64, error){
+		(*Empirical).Mean, (*Empirical).Variance, (*Empirical).Skewness,
+		(*Empirical).ExcessKurtosis, (*Empirical).Median, (*Empirical).Mode,
+	}
+	for i, stat := range stats {
+		expected, _ := stat(all)
+		if actual, _ := stat(a); !floatsNanoEqual(actual, expected) {
+			t.Fatalf("[%d] expected %v\n got %v\n", i, expected, actual)
+		}
+	}
+
+	// the median and mode reflect the merged values.
+	e, m := &Empirical{}, &Empirical{}
+	e.Add(1, 2, 3)
+	e.Median()
+	m.Add(3, 3)
+	e.Merge(m)
+	med, _ := e.Median()
+	mode, _ := e.Mode()
+	if med != 3 || mode != 3 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 3, 3, med, mode)
+	}
+
+	// weights are retained.
+	w := &Empirical{}
+	w.AddWeighted(10, 3)
+	e.Merge(w)
+	if mean, _ := e.Mean(); !floatsPicoEqual(mean, 42.0/8) || e.Size() != 8 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 42.0/8, 8, mean, e.Size())
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1)
+	if err := e.Merge(sk); err == nil {
+		t.Fatal("expected error merging sketched distribution")
+	}
+}
+
+func Test_Empirical_Remove(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	e, expected := &Empirical{}, &Empirical{}
+	var removed []float64
+	for i := 0; i < 1000; i++ {
+		v := rnd.NormFloat64()
+		e.Add(v)
+		if i%3 == 0 {
+			removed = append(removed, v)
+		} else {
+			expected.Add(v)
+		}
+	}
+	e.Median()
+
+	if err := e.Remove(removed...); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := []func(*Empirical) (float64, error){
+		(*Empirical).Mean, (*Empirical).Variance, (*Empirical).Skewness,
+		(*Empirical).ExcessKurtosis, (*Empirical).Median, (*Empirical).Mode,
+	}
+	for i, stat := range stats {
+		exp, _ := stat(expected)
+		if actual, _ := stat(e); !floatsNanoEqual(actual, exp) {
+			t.Fatalf("[%d] expected %v\n got %v\n", i, exp, actual)
+		}
+	}
+
+	// only a single occurrence of each value is removed, and weights are
+	// removed along with their values.
+	e = &Empirical{}
+	e.Add(1, 2, 2)
+	e.AddWeighted(4, 3)
+	if err := e.Remove(2, 4); err != nil {
+		t.Fatal(err)
+	}
+	if mean, _ := e.Mean(); mean != 1.5 || e.Size() != 2 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 1.5, 2, mean, e.Size())
+	}
+
+	// the distribution is unchanged if a value is missing.
+	if err := e.Remove(1, 1); err == nil {
+		t.Fatal("expected error removing missing value")
+	}
+	if e.Size() != 2 {
+		t.Fatalf("expected %v\n got %v\n", 2, e.Size())
+	}
+
+	if err := e.Remove(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Mean(); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1)
+	if err := sk.Remove(1); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+}
+
+// benchmarkStream benchmarks adding each of n values to a distribution,
+// followed by a call to stat, where stat is evaluated either by e, or by
+// resorting the sample added so far, as Empirical did before it held its
+// sample in an order-statistic tree.
+func benchmarkStream(b *testing.B, stat func(e *Empirical) (float64, error), resorted func(x []float64) float64) {
+	for _, n := range []int{100, 1000, 10000} {
+		rnd := rand.New(rand.NewSource(42))
+		values := make([]float64, n)
+		for i := range values {
+			// rounded values, so that the sample has ties.
+			values[i] = math.Round(100 * rnd.NormFloat64())
+		}
+
+		b.Run(fmt.Sprintf("tree/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				e := &Empirical{}
+				for _, v := range values {
+					e.Add(v)
+					stat(e)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("resort/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var x []float64
+				for _, v := range values {
+					x = append(x, v)
+					sort.Float64s(x)
+					resorted(x)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEmpirical_Median(b *testing.B) {
+	benchmarkStream(b, (*Empirical).Median, func(x []float64) float64 {
+		return hyndmanFan(x, 0.5, 7)
+	})
+}
+
+func BenchmarkEmpirical_Quantile(b *testing.B) {
+	quantile := func(e *Empirical) (float64, error) { return e.Quantile(0.9) }
+	benchmarkStream(b, quantile, func(x []float64) float64 {
+		return hyndmanFan(x, 0.9, 7)
+	})
+}
+
+func BenchmarkEmpirical_Mode(b *testing.B) {
+	benchmarkStream(b, (*Empirical).Mode, func(x []float64) float64 {
+		mode, count := x[0], 0
+		for i := 0; i < len(x); {
+			j := i + 1
+			for j < len(x) && x[j] == x[i] {
+				j++
+			}
+			if j-i > count {
+				mode, count = x[i], j-i
+			}
+			i = j
+		}
+		return mode
+	})
+}
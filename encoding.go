@@ -0,0 +1,594 @@
+package godist
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// encodingVersion is the version of the binary encoding of distributions,
+// which is written as the first byte of every encoding.
+const encodingVersion = 1
+
+// the kinds of Empirical distribution in the binary encoding.
+const (
+	empiricalUnweighted = iota
+	empiricalWeighted
+	empiricalSketched
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used
+// by encoding/gob. Rand is not encoded.
+func (beta Beta) MarshalBinary() ([]byte, error) {
+	b := []byte{encodingVersion}
+	b = appendFloat(b, beta.Alpha)
+	return appendFloat(b, beta.Beta), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a Beta
+// distribution encoded by MarshalBinary. Rand is left unchanged.
+func (beta *Beta) UnmarshalBinary(data []byte) error {
+	r := binaryReader{b: data, name: "Beta"}
+	r.version()
+	alpha, b := r.float64(), r.float64()
+	if err := r.done(); err != nil {
+		return err
+	}
+	beta.Alpha, beta.Beta = alpha, b
+	return nil
+}
+
+type betaJSON struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the shape parameters of
+// the distribution. Rand is not encoded.
+func (beta Beta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(betaJSON{Alpha: beta.Alpha, Beta: beta.Beta})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a Beta distribution
+// encoded by MarshalJSON. Rand is left unchanged.
+func (beta *Beta) UnmarshalJSON(data []byte) error {
+	var v betaJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	beta.Alpha, beta.Beta = v.Alpha, v.Beta
+	return nil
+}
+
+// empiricalState is the encoded state of an Empirical distribution: its
+// running moments, along with either each distinct value in the sample,
+// the number of times it occurs and, for a weighted sample, the weight of
+// each occurrence in the order they were added, or its quantile sketch.
+type empiricalState struct {
+	moments  moments
+	weighted bool
+	values   []float64
+	counts   []int
+	weights  []float64
+	sketch   *kllSketch
+}
+
+// state returns the encoded state of the distribution.
+func (e *Empirical) state() empiricalState {
+	s := empiricalState{moments: e.moments, weighted: e.tree.weighted, sketch: e.sketch}
+	e.tree.each(func(n *osnode) {
+		s.values = append(s.values, n.key)
+		s.counts = append(s.counts, n.count)
+		s.weights = append(s.weights, n.weights...)
+	})
+	return s
+}
+
+// restore replaces the distribution with the decoded state s, returning
+// an error if s is not a valid state. Rand is left unchanged.
+func (e *Empirical) restore(s empiricalState) error {
+	if s.sketch != nil {
+		if len(s.values) > 0 {
+			return decodeError("Empirical", "sketched distribution with values")
+		}
+		if s.sketch.k < 8 || len(s.sketch.levels) == 0 {
+			return decodeError("Empirical", "invalid sketch")
+		}
+		if float64(s.sketch.n) != s.moments.n {
+			return decodeError("Empirical", "sketch size does not match moments")
+		}
+
+		// each value at level h represents 2^h values added to the
+		// sketch, and every level above the bottom is sorted.
+		var n int
+		s.sketch.size = 0
+		for h, lvl := range s.sketch.levels {
+			if len(lvl) == 0 {
+				continue
+			} else if h >= 62 || len(lvl) > s.sketch.n>>h || len(lvl)<<h > s.sketch.n-n {
+				return decodeError("Empirical", "sketch size does not match levels")
+			} else if h > 0 && !sort.Float64sAreSorted(lvl) {
+				return decodeError("Empirical", fmt.Sprintf("unsorted sketch level %v", h))
+			}
+			n += len(lvl) << h
+			s.sketch.size += len(lvl)
+		}
+		if n != s.sketch.n {
+			return decodeError("Empirical", "sketch size does not match levels")
+		}
+		*e = Empirical{Rand: e.Rand, moments: s.moments, sketch: s.sketch}
+		return nil
+	}
+
+	if len(s.counts) != len(s.values) {
+		return decodeError("Empirical", "mismatched values and counts")
+	}
+
+	// every occurrence in a weighted sample has a weight, which bounds
+	// the counts by the size of the input.
+	var total int
+	for _, c := range s.counts {
+		if c < 1 || c > math.MaxInt32 {
+			return decodeError("Empirical", fmt.Sprintf("count %v", c))
+		}
+		total += c
+	}
+	if s.weighted && total != len(s.weights) {
+		return decodeError("Empirical", "weights do not match counts")
+	} else if !s.weighted && len(s.weights) > 0 {
+		return decodeError("Empirical", "unexpected weights")
+	}
+
+	tree := ostree{weighted: s.weighted}
+	var next int
+	for i, v := range s.values {
+		if !s.weighted {
+			tree.insertCount(v, s.counts[i])
+			continue
+		}
+
+		for _, w := range s.weights[next : next+s.counts[i]] {
+			if err := validWeight(w); err != nil || w == 0 {
+				return decodeError("Empirical", fmt.Sprintf("weight %v", w))
+			}
+			tree.insert(v, w)
+		}
+		next += s.counts[i]
+	}
+
+	// the moments must describe the decoded sample, allowing for rounding
+	// errors in the sum of the weights.
+	if n := tree.total(); !(math.Abs(n-s.moments.n) <= 1e-9*math.Max(1, n)) {
+		return decodeError("Empirical", "sample size does not match moments")
+	}
+
+	*e = Empirical{Rand: e.Rand, moments: s.moments, tree: tree}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used
+// by encoding/gob. The encoding holds the running moments of the
+// distribution, so that they are restored exactly, along with every
+// distinct value in the sample, or the quantile sketch of a sketched
+// distribution. Rand is not encoded.
+func (e *Empirical) MarshalBinary() ([]byte, error) {
+	s := e.state()
+
+	kind := empiricalUnweighted
+	if s.sketch != nil {
+		kind = empiricalSketched
+	} else if s.weighted {
+		kind = empiricalWeighted
+	}
+
+	b := []byte{encodingVersion, byte(kind)}
+	m := s.moments
+	for _, v := range []float64{m.n, m.mean, m.m2, m.m3, m.m4} {
+		b = appendFloat(b, v)
+	}
+
+	if s.sketch != nil {
+		b = binary.AppendUvarint(b, uint64(s.sketch.k))
+		b = binary.AppendUvarint(b, uint64(s.sketch.n))
+		b = binary.AppendUvarint(b, uint64(len(s.sketch.levels)))
+		for _, lvl := range s.sketch.levels {
+			b = binary.AppendUvarint(b, uint64(len(lvl)))
+			for _, v := range lvl {
+				b = appendFloat(b, v)
+			}
+		}
+		return b, nil
+	}
+
+	b = binary.AppendUvarint(b, uint64(len(s.values)))
+	var next int
+	for i, v := range s.values {
+		b = appendFloat(b, v)
+		b = binary.AppendUvarint(b, uint64(s.counts[i]))
+		if s.weighted {
+			for _, w := range s.weights[next : next+s.counts[i]] {
+				b = appendFloat(b, w)
+			}
+			next += s.counts[i]
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the
+// distribution with one encoded by MarshalBinary. Rand is left unchanged.
+func (e *Empirical) UnmarshalBinary(data []byte) error {
+	r := binaryReader{b: data, name: "Empirical"}
+	r.version()
+
+	var s empiricalState
+	kind := r.byte()
+	s.moments = moments{n: r.float64(), mean: r.float64(), m2: r.float64(), m3: r.float64(), m4: r.float64()}
+
+	switch kind {
+	case empiricalSketched:
+		s.sketch = &kllSketch{k: r.int(), n: r.int()}
+		s.sketch.levels = make([][]float64, r.len(1))
+		for h := range s.sketch.levels {
+			lvl := make([]float64, r.len(8))
+			for i := range lvl {
+				lvl[i] = r.float64()
+			}
+			s.sketch.levels[h] = lvl
+		}
+	case empiricalUnweighted, empiricalWeighted:
+		s.weighted = kind == empiricalWeighted
+		n := r.len(9)
+		s.values, s.counts = make([]float64, n), make([]int, n)
+		for i := 0; i < n; i++ {
+			s.values[i], s.counts[i] = r.float64(), r.int()
+			if s.weighted && s.counts[i]*8 <= len(r.b) {
+				for j := 0; j < s.counts[i]; j++ {
+					s.weights = append(s.weights, r.float64())
+				}
+			} else if s.weighted {
+				r.fail("truncated data")
+			}
+		}
+	default:
+		r.fail(fmt.Sprintf("kind %v", kind))
+	}
+
+	if err := r.done(); err != nil {
+		return err
+	}
+	return e.restore(s)
+}
+
+type empiricalJSON struct {
+	Moments  momentsJSON    `json:"moments"`
+	Weighted bool           `json:"weighted,omitempty"`
+	Values   []jsonFloat    `json:"values,omitempty"`
+	Counts   []int          `json:"counts,omitempty"`
+	Weights  []jsonFloat    `json:"weights,omitempty"`
+	Sketch   *kllSketchJSON `json:"sketch,omitempty"`
+}
+
+type momentsJSON struct {
+	N    jsonFloat `json:"n"`
+	Mean jsonFloat `json:"mean"`
+	M2   jsonFloat `json:"m2"`
+	M3   jsonFloat `json:"m3"`
+	M4   jsonFloat `json:"m4"`
+}
+
+type kllSketchJSON struct {
+	K      int           `json:"k"`
+	N      int           `json:"n"`
+	Levels [][]jsonFloat `json:"levels"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the same state as
+// MarshalBinary. Rand is not encoded.
+func (e *Empirical) MarshalJSON() ([]byte, error) {
+	s := e.state()
+	m := s.moments
+	v := empiricalJSON{
+		Moments: momentsJSON{
+			N: jsonFloat(m.n), Mean: jsonFloat(m.mean),
+			M2: jsonFloat(m.m2), M3: jsonFloat(m.m3), M4: jsonFloat(m.m4),
+		},
+		Weighted: s.weighted,
+		Values:   toJSONFloats(s.values),
+		Counts:   s.counts,
+		Weights:  toJSONFloats(s.weights),
+	}
+	if s.sketch != nil {
+		v.Sketch = &kllSketchJSON{K: s.sketch.k, N: s.sketch.n, Levels: make([][]jsonFloat, len(s.sketch.levels))}
+		for h, lvl := range s.sketch.levels {
+			v.Sketch.Levels[h] = toJSONFloats(lvl)
+			if v.Sketch.Levels[h] == nil {
+				v.Sketch.Levels[h] = []jsonFloat{}
+			}
+		}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the distribution
+// with one encoded by MarshalJSON. Rand is left unchanged.
+func (e *Empirical) UnmarshalJSON(data []byte) error {
+	var v empiricalJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	m := v.Moments
+	s := empiricalState{
+		moments: moments{
+			n: float64(m.N), mean: float64(m.Mean),
+			m2: float64(m.M2), m3: float64(m.M3), m4: float64(m.M4),
+		},
+		weighted: v.Weighted,
+		values:   fromJSONFloats(v.Values),
+		counts:   v.Counts,
+		weights:  fromJSONFloats(v.Weights),
+	}
+	if v.Sketch != nil {
+		s.sketch = &kllSketch{k: v.Sketch.K, n: v.Sketch.N, levels: make([][]float64, len(v.Sketch.Levels))}
+		for h, lvl := range v.Sketch.Levels {
+			s.sketch.levels[h] = fromJSONFloats(lvl)
+		}
+	}
+	return e.restore(s)
+}
+
+// An Envelope holds a Distribution along with a tag naming its type, so
+// that a collection of distributions of different types, such as a
+// []Envelope, can be encoded and decoded with encoding/json,
+// encoding/gob, or MarshalBinary.
+//
+// The Beta and *Empirical distributions are supported, which are tagged
+// "beta" and "empirical" respectively. A decoded Beta distribution is
+// held by value, and a decoded Empirical distribution by pointer.
+type Envelope struct {
+	Distribution Distribution
+}
+
+type envelopeJSON struct {
+	Type         string          `json:"type"`
+	Distribution json.RawMessage `json:"distribution"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the distribution as an
+// object with its tag as the "type" field, and its own JSON encoding as
+// the "distribution" field.
+func (env Envelope) MarshalJSON() ([]byte, error) {
+	tag, err := envelopeTag(env.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(env.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelopeJSON{Type: tag, Distribution: data})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a distribution
+// encoded by MarshalJSON.
+func (env *Envelope) UnmarshalJSON(data []byte) error {
+	var v envelopeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return env.decode(v.Type, v.Distribution, json.Unmarshal)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used
+// by encoding/gob, encoding the tag of the distribution followed by its
+// own binary encoding.
+func (env Envelope) MarshalBinary() ([]byte, error) {
+	tag, err := envelopeTag(env.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := env.Distribution.(interface{ MarshalBinary() ([]byte, error) }).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	b := binary.AppendUvarint(nil, uint64(len(tag)))
+	b = append(b, tag...)
+	return append(b, data...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a
+// distribution encoded by MarshalBinary.
+func (env *Envelope) UnmarshalBinary(data []byte) error {
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)-k) {
+		return decodeError("Envelope", "invalid tag")
+	}
+	tag := string(data[k : k+int(n)])
+
+	unmarshal := func(data []byte, v any) error {
+		return v.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(data)
+	}
+	return env.decode(tag, data[k+int(n):], unmarshal)
+}
+
+// decode sets the distribution in the envelope to the distribution with
+// the provided tag, decoded from data using unmarshal.
+func (env *Envelope) decode(tag string, data []byte, unmarshal func([]byte, any) error) error {
+	switch tag {
+	case "beta":
+		var beta Beta
+		if err := unmarshal(data, &beta); err != nil {
+			return err
+		}
+		env.Distribution = beta
+	case "empirical":
+		e := &Empirical{}
+		if err := unmarshal(data, e); err != nil {
+			return err
+		}
+		env.Distribution = e
+	default:
+		msg := fmt.Sprintf("distribution type %q not supported.", tag)
+		return UnsupportedError{S: msg}
+	}
+	return nil
+}
+
+// envelopeTag returns the tag naming the type of d in an Envelope.
+func envelopeTag(d Distribution) (string, error) {
+	switch d.(type) {
+	case Beta, *Beta:
+		return "beta", nil
+	case *Empirical:
+		return "empirical", nil
+	}
+	msg := fmt.Sprintf("encoding distribution of type %T not supported.", d)
+	return "", UnsupportedError{S: msg}
+}
+
+// A jsonFloat is a float64 which is encoded as a JSON number where it is
+// finite, and otherwise as one of the strings "NaN", "+Inf" or "-Inf",
+// which encoding/json cannot otherwise represent.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return json.Marshal(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return json.Marshal(v)
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || !(math.IsNaN(v) || math.IsInf(v, 0)) {
+			return decodeError("float", fmt.Sprintf("value %q", s))
+		}
+		*f = jsonFloat(v)
+		return nil
+	}
+
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+func toJSONFloats(x []float64) []jsonFloat {
+	if x == nil {
+		return nil
+	}
+	fs := make([]jsonFloat, len(x))
+	for i, v := range x {
+		fs[i] = jsonFloat(v)
+	}
+	return fs
+}
+
+func fromJSONFloats(fs []jsonFloat) []float64 {
+	if fs == nil {
+		return nil
+	}
+	x := make([]float64, len(fs))
+	for i, f := range fs {
+		x[i] = float64(f)
+	}
+	return x
+}
+
+// appendFloat appends the little-endian IEEE 754 encoding of v to b.
+func appendFloat(b []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+// binaryReader decodes the binary encoding of the named type from b. The
+// first error encountered is retained, after which every read returns a
+// zero value.
+type binaryReader struct {
+	b    []byte
+	name string
+	err  error
+}
+
+// version reads the encoding version, which must be encodingVersion.
+func (r *binaryReader) version() {
+	if v := r.byte(); r.err == nil && v != encodingVersion {
+		r.fail(fmt.Sprintf("version %v", v))
+	}
+}
+
+func (r *binaryReader) byte() byte {
+	if r.err != nil || len(r.b) < 1 {
+		r.fail("truncated data")
+		return 0
+	}
+	v := r.b[0]
+	r.b = r.b[1:]
+	return v
+}
+
+func (r *binaryReader) float64() float64 {
+	if r.err != nil || len(r.b) < 8 {
+		r.fail("truncated data")
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.b))
+	r.b = r.b[8:]
+	return v
+}
+
+func (r *binaryReader) int() int {
+	if r.err != nil {
+		return 0
+	}
+	v, k := binary.Uvarint(r.b)
+	if k <= 0 || v > math.MaxInt32 {
+		r.fail("invalid integer")
+		return 0
+	}
+	r.b = r.b[k:]
+	return int(v)
+}
+
+// len reads the length of a sequence whose elements are each encoded in
+// at least size bytes, failing if the remaining data is too short to
+// hold it, so that a corrupt length cannot cause a large allocation.
+func (r *binaryReader) len(size int) int {
+	n := r.int()
+	if n*size > len(r.b) {
+		r.fail("truncated data")
+		return 0
+	}
+	return n
+}
+
+func (r *binaryReader) fail(reason string) {
+	if r.err == nil {
+		r.err = decodeError(r.name, reason)
+	}
+}
+
+// done returns the first error encountered, or an error if any data
+// remains unread.
+func (r *binaryReader) done() error {
+	if r.err == nil && len(r.b) > 0 {
+		r.fail("unexpected trailing data")
+	}
+	return r.err
+}
+
+func decodeError(name, reason string) error {
+	msg := fmt.Sprintf("cannot decode %v: %v.", name, reason)
+	return InvalidDistributionError{S: msg}
+}
@@ -0,0 +1,266 @@
+package godist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// codecs round-trip a value through each of the supported encodings,
+// decoding into out.
+var codecs = []struct {
+	Name      string
+	RoundTrip func(in, out any) error
+}{
+	{"json", func(in, out any) error {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, out)
+	}},
+	{"gob", func(in, out any) error {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			return err
+		}
+		return gob.NewDecoder(&buf).Decode(out)
+	}},
+	{"binary", func(in, out any) error {
+		data, err := in.(interface{ MarshalBinary() ([]byte, error) }).MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return out.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(data)
+	}},
+}
+
+func Test_Beta_Encoding(t *testing.T) {
+	expected := Beta{Alpha: 2.5, Beta: 0.125, Rand: rand.New(rand.NewSource(1))}
+	for _, codec := range codecs {
+		var actual Beta
+		if err := codec.RoundTrip(expected, &actual); err != nil {
+			t.Fatalf("expected no error\n got %v for %v\n", err, codec.Name)
+		}
+		if actual.Alpha != expected.Alpha || actual.Beta != expected.Beta || actual.Rand != nil {
+			t.Fatalf("expected %v\n got %v for %v\n", expected, actual, codec.Name)
+		}
+	}
+
+	if data, _ := json.Marshal(expected); string(data) != `{"alpha":2.5,"beta":0.125}` {
+		t.Fatalf("expected %v\n got %v\n", `{"alpha":2.5,"beta":0.125}`, string(data))
+	}
+}
+
+func Test_Empirical_Encoding(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+
+	unweighted := &Empirical{}
+	weighted := &Empirical{}
+	sketched := NewSketchedEmpirical(0)
+	sketched.Rand = rnd
+	for i := 0; i < 2000; i++ {
+		v := math.Round(10*rnd.NormFloat64()) / 10
+		unweighted.Add(v)
+		sketched.Add(v)
+		weighted.AddWeighted(v, rnd.ExpFloat64())
+	}
+	unweighted.Add(math.Inf(1), math.Inf(-1))
+
+	examples := []struct {
+		Name string
+		E    *Empirical
+	}{
+		{"empty", &Empirical{}},
+		{"unweighted", unweighted},
+		{"weighted", weighted},
+		{"sketched", sketched},
+	}
+
+	for _, example := range examples {
+		for _, codec := range codecs {
+			actual := &Empirical{}
+			if err := codec.RoundTrip(example.E, actual); err != nil {
+				t.Fatalf("expected no error\n got %v for %v, %v\n", err, example.Name, codec.Name)
+			}
+
+			// the running moments are restored exactly, where the infinite
+			// values in the unweighted sample give NaN moments.
+			if !sameMoments(actual.moments, example.E.moments) {
+				t.Fatalf("expected %v\n got %v for %v, %v\n", example.E.moments, actual.moments, example.Name, codec.Name)
+			}
+			if actual.tree.weighted != example.E.tree.weighted || (actual.sketch == nil) != (example.E.sketch == nil) {
+				t.Fatalf("expected matching kind for %v, %v\n", example.Name, codec.Name)
+			}
+
+			for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+				expected, eerr := example.E.Quantile(p)
+				q, err := actual.Quantile(p)
+				if (err == nil) != (eerr == nil) || q != expected {
+					t.Fatalf("expected %v\n got %v for %v, %v\n", expected, q, example.Name, codec.Name)
+				}
+			}
+			if example.E.sketch == nil {
+				expected, _ := example.E.Mode()
+				if mode, _ := actual.Mode(); mode != expected {
+					t.Fatalf("expected %v\n got %v for %v, %v\n", expected, mode, example.Name, codec.Name)
+				}
+				if ess := actual.EffectiveSize(); !floatsPicoEqual(ess, example.E.EffectiveSize()) {
+					t.Fatalf("expected %v\n got %v for %v, %v\n", example.E.EffectiveSize(), ess, example.Name, codec.Name)
+				}
+			}
+
+			// the decoded distribution can continue to be updated.
+			actual.Add(1)
+		}
+	}
+
+	// weights of the same value remain in the order they were added.
+	e := &Empirical{}
+	e.AddWeighted(1, 2)
+	e.AddWeighted(1, 3)
+	data, _ := e.MarshalBinary()
+	actual := &Empirical{}
+	actual.UnmarshalBinary(data)
+	if w, _ := actual.tree.remove(1); w != 2 {
+		t.Fatalf("expected %v\n got %v\n", 2, w)
+	}
+}
+
+func sameMoments(a, b moments) bool {
+	x := []float64{a.n, a.mean, a.m2, a.m3, a.m4}
+	y := []float64{b.n, b.mean, b.m2, b.m3, b.m4}
+	for i := range x {
+		if x[i] != y[i] && !(math.IsNaN(x[i]) && math.IsNaN(y[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_Empirical_Encoding_Invalid(t *testing.T) {
+	e := &Empirical{}
+	e.AddWeighted(1, 2)
+	e.AddWeighted(3, 4)
+	data, _ := e.MarshalBinary()
+
+	// every truncation of the encoding, along with trailing data and an
+	// unknown version, is rejected.
+	for i := 0; i < len(data); i++ {
+		if err := (&Empirical{}).UnmarshalBinary(data[:i]); err == nil {
+			t.Fatalf("expected error decoding %v of %v bytes\n", i, len(data))
+		}
+	}
+	if err := (&Empirical{}).UnmarshalBinary(append(data, 0)); err == nil {
+		t.Fatal("expected error on trailing data")
+	}
+	if err := (&Empirical{}).UnmarshalBinary(append([]byte{2}, data[1:]...)); err == nil {
+		t.Fatal("expected error on unknown version")
+	}
+
+	for _, s := range []string{
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"values":[1],"counts":[0]}`,
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"values":[1]}`,
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"weighted":true,"values":[1],"counts":[1],"weights":[-1]}`,
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"weighted":true,"values":[1],"counts":[1]}`,
+		`{"moments":{"n":"1","mean":1,"m2":0,"m3":0,"m4":0}}`,
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"sketch":{"k":2,"n":1,"levels":[[1]]}}`,
+		`{"moments":{"n":2,"mean":1,"m2":0,"m3":0,"m4":0},"sketch":{"k":8,"n":1,"levels":[[1]]}}`,
+		`{"moments":{"n":0,"mean":0,"m2":0,"m3":0,"m4":0},"values":[1,2],"counts":[50000000,1]}`,
+		`{"moments":{"n":2,"mean":1,"m2":0,"m3":0,"m4":0},"weighted":true,"values":[1],"counts":[1],"weights":[1]}`,
+		`{"moments":{"n":1,"mean":1,"m2":0,"m3":0,"m4":0},"values":[1],"counts":[1],"weights":[1]}`,
+		`{"moments":{"n":5,"mean":1,"m2":0,"m3":0,"m4":0},"sketch":{"k":8,"n":5,"levels":[[1]]}}`,
+		`{"moments":{"n":5,"mean":1,"m2":0,"m3":0,"m4":0},"sketch":{"k":8,"n":5,"levels":[[1],[3,1]]}}`,
+		`{"moments":{"n":4,"mean":1,"m2":0,"m3":0,"m4":0},"sketch":{"k":8,"n":4,"levels":[[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[],[1]]}}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &Empirical{}); err == nil {
+			t.Fatalf("expected error decoding %v\n", s)
+		}
+	}
+}
+
+func Test_Empirical_Encoding_Counts(t *testing.T) {
+	// repeated values are restored without adding each occurrence.
+	s := `{"moments":{"n":2000000001,"mean":1,"m2":0,"m3":0,"m4":0},"values":[1,2],"counts":[2000000000,1]}`
+	e := &Empirical{}
+	if err := json.Unmarshal([]byte(s), e); err != nil {
+		t.Fatal(err)
+	}
+	if mode, _ := e.Mode(); mode != 1 || e.tree.len() != 2000000001 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 1, 2000000001, mode, e.tree.len())
+	}
+}
+
+func Test_Envelope(t *testing.T) {
+	e := &Empirical{}
+	e.Add(1, 2, 2, 3)
+	ds := []Envelope{{Beta{Alpha: 2, Beta: 3}}, {e}, {&Beta{Alpha: 1, Beta: 1}}}
+
+	for _, codec := range codecs[:2] {
+		var actual []Envelope
+		if err := codec.RoundTrip(ds, &actual); err != nil {
+			t.Fatalf("expected no error\n got %v for %v\n", err, codec.Name)
+		}
+		if len(actual) != len(ds) {
+			t.Fatalf("expected %v\n got %v for %v\n", len(ds), len(actual), codec.Name)
+		}
+
+		for i, env := range actual {
+			em, _ := ds[i].Distribution.Mean()
+			am, _ := env.Distribution.Mean()
+			if am != em {
+				t.Fatalf("expected %v\n got %v for %v\n", em, am, codec.Name)
+			}
+		}
+		if _, ok := actual[0].Distribution.(Beta); !ok {
+			t.Fatalf("expected %T\n got %T\n", Beta{}, actual[0].Distribution)
+		}
+		if _, ok := actual[1].Distribution.(*Empirical); !ok {
+			t.Fatalf("expected %T\n got %T\n", e, actual[1].Distribution)
+		}
+	}
+
+	env := Envelope{e}
+	data, _ := env.MarshalBinary()
+	var actual Envelope
+	if err := actual.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := actual.Distribution.Median(); m != 2 {
+		t.Fatalf("expected %v\n got %v\n", 2, m)
+	}
+
+	if _, err := json.Marshal(Envelope{Normal{Mu: 0, Sigma: 1}}); err == nil {
+		t.Fatal("expected error on unsupported distribution")
+	}
+	if err := json.Unmarshal([]byte(`{"type":"normal","distribution":{}}`), &actual); err == nil {
+		t.Fatal("expected error on unknown type")
+	}
+	if err := actual.UnmarshalBinary([]byte{200}); err == nil {
+		t.Fatal("expected error on invalid tag")
+	}
+}
+
+func Test_jsonFloat(t *testing.T) {
+	for _, v := range []float64{0, -1.5, math.MaxFloat64, math.Inf(1), math.Inf(-1), math.NaN()} {
+		data, err := json.Marshal(jsonFloat(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual jsonFloat
+		if err := json.Unmarshal(data, &actual); err != nil {
+			t.Fatal(err)
+		}
+		if a := float64(actual); a != v && !(math.IsNaN(a) && math.IsNaN(v)) {
+			t.Fatalf("expected %v\n got %v\n", v, a)
+		}
+	}
+
+	var f jsonFloat
+	if err := json.Unmarshal([]byte(`"1.5"`), &f); err == nil {
+		t.Fatal("expected error on finite string")
+	}
+}
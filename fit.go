@@ -0,0 +1,111 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// maximum number of Newton iterations used by FitBetaMLE.
+const fitMaxIter = 100
+
+// FitBetaMoments returns the Beta distribution whose mean and variance
+// match those of the sample in e, i.e., the method of moments estimate.
+//
+// All values in e must lie strictly within (0, 1), and the sample must
+// have a non-zero variance. Note that the variance of an Empirical
+// distribution is the population variance of its sample.
+func FitBetaMoments(e *Empirical) (Beta, error) {
+	if err := validBetaSample(e); err != nil {
+		return Beta{}, err
+	}
+
+	m, _ := e.Mean()
+	v, _ := e.Variance()
+	c := m*(1-m)/v - 1
+	return Beta{Alpha: m * c, Beta: (1 - m) * c}, nil
+}
+
+// FitBetaMLE returns the maximum-likelihood estimate of the Beta
+// distribution that generated the sample in e.
+//
+// The estimate is found by solving the score equations
+//
+//	ψ(α) - ψ(α + β) = mean(log x)
+//	ψ(β) - ψ(α + β) = mean(log(1 - x))
+//
+// using Newton's method, starting from the method of moments estimate.
+// For a weighted distribution the means are weighted, giving the
+// weighted maximum-likelihood estimate.
+//
+// All values in e must lie strictly within (0, 1), and the sample must
+// have a non-zero variance.
+func FitBetaMLE(e *Empirical) (Beta, error) {
+	b, err := FitBetaMoments(e)
+	if err != nil {
+		return Beta{}, err
+	}
+
+	var lg1, lg2 float64
+	e.tree.each(func(n *osnode) {
+		lg1 += n.weight * math.Log(n.key)
+		lg2 += n.weight * math.Log1p(-n.key)
+	})
+	lg1, lg2 = lg1/e.n, lg2/e.n
+
+	a, bb := b.Alpha, b.Beta
+	for i := 0; i < fitMaxIter; i++ {
+		dab, t := special.Digamma(a+bb), special.Trigamma(a+bb)
+		g1 := special.Digamma(a) - dab - lg1
+		g2 := special.Digamma(bb) - dab - lg2
+
+		// solve J·Δ = g, where J is the Jacobian of the score equations.
+		j11, j22 := special.Trigamma(a)-t, special.Trigamma(bb)-t
+		det := j11*j22 - t*t
+		da := (j22*g1 + t*g2) / det
+		db := (t*g1 + j11*g2) / det
+
+		// halve the step until the estimate remains valid.
+		step := 1.0
+		for a-step*da <= 0 || bb-step*db <= 0 {
+			step /= 2
+		}
+		a, bb = a-step*da, bb-step*db
+
+		if math.Abs(step*da) <= 1e-14*a && math.Abs(step*db) <= 1e-14*bb {
+			break
+		}
+	}
+	return Beta{Alpha: a, Beta: bb}, nil
+}
+
+// validBetaSample returns an error if a Beta distribution cannot be fitted
+// to the sample in e.
+func validBetaSample(e *Empirical) error {
+	if e.n == 0 {
+		msg := "cannot fit Beta distribution to an empty sample."
+		return InvalidDistributionError{S: msg}
+	} else if e.sketch != nil {
+		msg := "cannot fit Beta distribution to a sketched sample."
+		return UnsupportedError{S: msg}
+	}
+
+	var invalid bool
+	var value float64
+	e.tree.each(func(n *osnode) {
+		if !invalid && !(n.key > 0 && n.key < 1) {
+			invalid, value = true, n.key
+		}
+	})
+	if invalid {
+		msg := fmt.Sprintf("cannot fit Beta distribution to sample value %v outside (0, 1).", value)
+		return InvalidDistributionError{S: msg}
+	}
+
+	if v, _ := e.Variance(); v == 0 {
+		msg := "cannot fit Beta distribution to a sample with zero variance."
+		return InvalidDistributionError{S: msg}
+	}
+	return nil
+}
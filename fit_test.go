@@ -0,0 +1,114 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/e-dard/godist/special"
+)
+
+func Test_FitBetaMoments(t *testing.T) {
+	e := &Empirical{}
+	e.Add(0.2, 0.4, 0.6)
+
+	actual, err := FitBetaMoments(e)
+	if err != nil {
+		t.Fatalf("expected no error\n got %v\n", err)
+	}
+
+	if !floatsPicoEqual(actual.Alpha, 3.2) || !floatsPicoEqual(actual.Beta, 4.8) {
+		t.Fatalf("expected %v\n got %v\n", Beta{Alpha: 3.2, Beta: 4.8}, actual)
+	}
+}
+
+func Test_FitBetaMLE(t *testing.T) {
+	e := &Empirical{}
+	e.Add(0.2, 0.4, 0.6, 0.35, 0.9)
+
+	actual, err := FitBetaMLE(e)
+	if err != nil {
+		t.Fatalf("expected no error\n got %v\n", err)
+	}
+
+	// the estimate must satisfy the score equations.
+	var lg1, lg2 float64
+	for _, v := range e.sortedSample() {
+		lg1 += math.Log(v) / e.n
+		lg2 += math.Log1p(-v) / e.n
+	}
+
+	a, b := actual.Alpha, actual.Beta
+	if g1 := special.Digamma(a) - special.Digamma(a+b); !floatsPicoEqual(g1, lg1) {
+		t.Fatalf("expected %v\n got %v\n", lg1, g1)
+	}
+	if g2 := special.Digamma(b) - special.Digamma(a+b); !floatsPicoEqual(g2, lg2) {
+		t.Fatalf("expected %v\n got %v\n", lg2, g2)
+	}
+}
+
+// both estimates should recover the parameters of a large sample.
+func Test_FitBeta_Sample(t *testing.T) {
+	inputs := []Beta{
+		Beta{Alpha: 2, Beta: 5},
+		Beta{Alpha: 0.4, Beta: 0.6},
+		Beta{Alpha: 30, Beta: 12},
+	}
+
+	for _, b := range inputs {
+		b.Rand = rand.New(rand.NewSource(1))
+		e := &Empirical{}
+		for i := 0; i < 20000; i++ {
+			v, _ := b.Float64()
+			e.Add(v)
+		}
+
+		for _, fit := range []func(*Empirical) (Beta, error){FitBetaMoments, FitBetaMLE} {
+			actual, err := fit(e)
+			if err != nil {
+				t.Fatalf("expected no error\n got %v\n", err)
+			}
+
+			if !floatsEqual(actual.Alpha, b.Alpha, 0.05*b.Alpha) ||
+				!floatsEqual(actual.Beta, b.Beta, 0.05*b.Beta) {
+				t.Fatalf("expected %v\n got %v\n", b, actual)
+			}
+		}
+	}
+}
+
+func Test_FitBeta_Invalid(t *testing.T) {
+	type Example struct {
+		in  []float64
+		err error
+	}
+
+	examples := []Example{
+		Example{
+			in:  nil,
+			err: InvalidDistributionError{S: "cannot fit Beta distribution to an empty sample."},
+		},
+		Example{
+			in:  []float64{0.5, 1},
+			err: InvalidDistributionError{S: "cannot fit Beta distribution to sample value 1 outside (0, 1)."},
+		},
+		Example{
+			in:  []float64{0.2, -0.1},
+			err: InvalidDistributionError{S: "cannot fit Beta distribution to sample value -0.1 outside (0, 1)."},
+		},
+		Example{
+			in:  []float64{0.3, 0.3, 0.3},
+			err: InvalidDistributionError{S: "cannot fit Beta distribution to a sample with zero variance."},
+		},
+	}
+
+	for _, ex := range examples {
+		e := &Empirical{}
+		e.Add(ex.in...)
+		for _, fit := range []func(*Empirical) (Beta, error){FitBetaMoments, FitBetaMLE} {
+			if _, err := fit(e); err != ex.err {
+				t.Fatalf("expected %v\n got %v\n", ex.err, err)
+			}
+		}
+	}
+}
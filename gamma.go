@@ -0,0 +1,210 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// A Gamma distribution is a continuous probability distribution on the
+// range [0, ∞), which can be formed using a shape parameter α > 0 and
+// rate parameter β > 0.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type Gamma struct {
+	Shape float64
+	Rate  float64
+	Rand  Rand
+}
+
+// Mean returns the mean of the Gamma distribution, i.e., α / β.
+func (g Gamma) Mean() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return g.Shape / g.Rate, nil
+}
+
+// Median returns the median of the Gamma distribution.
+//
+// Since there is no closed-form expression for the median of a Gamma
+// distribution, Median is calculated as Quantile(0.5).
+func (g Gamma) Median() (float64, error) {
+	return g.Quantile(0.5)
+}
+
+// Mode returns the mode of the Gamma distribution, i.e., (α - 1) / β.
+//
+// The Mode is only supported where α ≥ 1, since otherwise the density
+// is unbounded at zero, in which case an InvalidDistributionError is
+// returned, as by Beta.Mode.
+func (g Gamma) Mode() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if g.Shape < 1 {
+		msg := fmt.Sprintf("Mode not supported for Gamma Distribution [α = %v, β = %v]", g.Shape, g.Rate)
+		return 0, InvalidDistributionError{S: msg}
+	}
+	return (g.Shape - 1) / g.Rate, nil
+}
+
+// Variance returns the variance of the Gamma distribution, i.e., α / β².
+func (g Gamma) Variance() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return g.Shape / (g.Rate * g.Rate), nil
+}
+
+// Skewness returns the skewness of the Gamma distribution, i.e., 2 / √α.
+func (g Gamma) Skewness() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return 2 / math.Sqrt(g.Shape), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Gamma distribution,
+// i.e., 6 / α.
+func (g Gamma) ExcessKurtosis() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return 6 / g.Shape, nil
+}
+
+// Entropy returns the differential entropy of the Gamma distribution in
+// nats, i.e.,
+//
+//	α - ln β + ln Γ(α) + (1 - α)ψ(α)
+//
+// where ψ is the digamma function.
+func (g Gamma) Entropy() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return g.Shape - math.Log(g.Rate) + special.LogGamma(g.Shape) + (1-g.Shape)*special.Digamma(g.Shape), nil
+}
+
+// KL returns the Kullback-Leibler divergence of other from the Gamma
+// distribution in nats.
+func (g Gamma) KL(other Gamma) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	} else if ok, err := other.valid(); !ok {
+		return 0, err
+	}
+
+	a1, b1, a2, b2 := g.Shape, g.Rate, other.Shape, other.Rate
+	return (a1-a2)*special.Digamma(a1) - special.LogGamma(a1) + special.LogGamma(a2) + a2*math.Log(b1/b2) + a1*(b2-b1)/b1, nil
+}
+
+// PDF returns the value of the probability density function of the Gamma
+// distribution at x.
+func (g Gamma) PDF(x float64) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if x < 0 {
+		return 0, nil
+	}
+
+	lp, err := g.LogPDF(x)
+	return math.Exp(lp), err
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function of the Gamma distribution at x.
+func (g Gamma) LogPDF(x float64) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if x < 0 {
+		return math.Inf(-1), nil
+	}
+
+	var lx float64
+	if g.Shape != 1 {
+		lx = (g.Shape - 1) * math.Log(x)
+	}
+	return g.Shape*math.Log(g.Rate) + lx - g.Rate*x - special.LogGamma(g.Shape), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Gamma distribution at x, i.e., the regularized lower incomplete gamma
+// function P(α, βx).
+func (g Gamma) CDF(x float64) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return special.RegIncGamma(g.Shape, g.Rate*x), nil
+}
+
+// Quantile returns the p-quantile of the Gamma distribution, i.e., the
+// value x such that CDF(x) = p.
+func (g Gamma) Quantile(p float64) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return 0, UnsupportedError{S: msg}
+	}
+	return special.InvRegIncGamma(g.Shape, p) / g.Rate, nil
+}
+
+// Float64 returns a random variate from the Gamma distribution.
+func (g Gamma) Float64() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return genGammaMarsagliaTsang(g.Rand, g.Shape) / g.Rate, nil
+}
+
+// genGammaMarsagliaTsang generates a random variate from a Gamma
+// distribution with the provided shape and unit rate, according to the
+// algorithm described by Marsaglia and Tsang in "A simple method for
+// generating gamma variables" (2000).
+//
+// Where shape < 1, a variate is generated for shape + 1 and boosted by
+// multiplying it by U^(1/shape), as described in the same paper.
+func genGammaMarsagliaTsang(rnd Rand, shape float64) float64 {
+	if shape < 1 {
+		u := randFloat64(rnd)
+		return genGammaMarsagliaTsang(rnd, shape+1) * math.Pow(u, 1/shape)
+	}
+
+	d := shape - 1.0/3.0
+	c := 1 / math.Sqrt(9*d)
+	for {
+		var x, v float64
+		for v <= 0 {
+			x = genStdNormal(rnd)
+			v = 1 + c*x
+		}
+		v = v * v * v
+
+		u := randFloat64(rnd)
+		if u < 1-0.0331*x*x*x*x {
+			return d * v
+		}
+		if math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+func (g Gamma) valid() (bool, error) {
+	if !(g.Shape > 0) || !(g.Rate > 0) {
+		msg := fmt.Sprintf("Invalid Gamma Distribution: [α = %v, β = %v]", g.Shape, g.Rate)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
@@ -0,0 +1,278 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type gammaExample struct {
+	in  Gamma
+	x   float64
+	err error
+	out float64
+}
+
+func Test_Gamma_Imp_Distribution(t *testing.T) {
+	var _ Distribution = Gamma{}
+	var _ Moments = Gamma{}
+}
+
+func Test_Gamma_Mean(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, out: 1},
+		gammaExample{in: Gamma{Shape: 3, Rate: 2}, out: 1.5},
+		gammaExample{in: Gamma{Shape: 0.5, Rate: 10}, out: 0.05},
+		gammaExample{
+			in:  Gamma{Shape: 0, Rate: 1},
+			err: fmt.Errorf("Invalid Gamma Distribution: [α = 0, β = 1]"),
+		},
+		gammaExample{
+			in:  Gamma{Shape: 1, Rate: -1},
+			err: fmt.Errorf("Invalid Gamma Distribution: [α = 1, β = -1]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Mean()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Median(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, out: math.Ln2},
+		gammaExample{in: Gamma{Shape: 1, Rate: 4}, out: math.Ln2 / 4},
+		gammaExample{in: Gamma{Shape: 0.5, Rate: 0.5}, out: 0.454936423119572},
+		gammaExample{
+			in:  Gamma{Shape: 0, Rate: 0},
+			err: fmt.Errorf("Invalid Gamma Distribution: [α = 0, β = 0]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Median()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Mode(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, out: 0},
+		gammaExample{in: Gamma{Shape: 5, Rate: 2}, out: 2},
+		gammaExample{
+			in:  Gamma{Shape: 0.5, Rate: 2},
+			err: fmt.Errorf("Mode not supported for Gamma Distribution [α = 0.5, β = 2]"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Mode()
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		} else if _, ok := err.(InvalidDistributionError); ex.err != nil && !ok {
+			t.Fatalf("expected %T\n got %T\n", InvalidDistributionError{}, err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Variance(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, out: 1},
+		gammaExample{in: Gamma{Shape: 3, Rate: 2}, out: 0.75},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Variance()
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Skewness_Kurtosis(t *testing.T) {
+	g := Gamma{Shape: 4, Rate: 3}
+	skew, _ := g.Skewness()
+	kurtosis, _ := g.ExcessKurtosis()
+	if !floatsPicoEqual(skew, 1) || !floatsPicoEqual(kurtosis, 1.5) {
+		t.Fatalf("expected 1, 1.5\n got %v, %v\n", skew, kurtosis)
+	}
+
+	if _, err := (Gamma{Shape: 1}).ExcessKurtosis(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Gamma_Entropy_KL(t *testing.T) {
+	// With α = 1, the Gamma is an Exponential distribution.
+	h, _ := Gamma{Shape: 1, Rate: 2}.Entropy()
+	if !floatsPicoEqual(h, 0.3068528194400547) {
+		t.Fatalf("expected %v\n got %v\n", 0.3068528194400547, h)
+	}
+
+	// ln(λ₁/λ₂) + λ₂/λ₁ - 1 between Exponential distributions.
+	kl, _ := Gamma{Shape: 1, Rate: 2}.KL(Gamma{Shape: 1, Rate: 1})
+	if !floatsPicoEqual(kl, math.Ln2-0.5) {
+		t.Fatalf("expected %v\n got %v\n", math.Ln2-0.5, kl)
+	}
+}
+
+func Test_Gamma_PDF(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: 0, out: 1},
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: -1, out: 0},
+		gammaExample{in: Gamma{Shape: 2, Rate: 1}, x: 1, out: 0.36787944117144233},
+		gammaExample{in: Gamma{Shape: 3, Rate: 2}, x: 1.5, out: 0.44808361531077556},
+		gammaExample{in: Gamma{Shape: 2, Rate: 1}, x: 0, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.PDF(ex.x)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_CDF(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: 2, out: 0.8646647167633873},
+		gammaExample{in: Gamma{Shape: 1, Rate: 0.5}, x: 3, out: 0.7768698398515702},
+		gammaExample{in: Gamma{Shape: 3, Rate: 1}, x: 2.5, out: 0.4561868841166705},
+		gammaExample{in: Gamma{Shape: 5, Rate: 2}, x: 5, out: 0.970747311923039},
+		gammaExample{in: Gamma{Shape: 0.5, Rate: 1}, x: 0.3, out: 0.5614219739190001},
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: -1, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.CDF(ex.x)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Quantile(t *testing.T) {
+	examples := []gammaExample{
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: 0, out: 0},
+		gammaExample{in: Gamma{Shape: 1, Rate: 1}, x: 0.8646647167633873, out: 2},
+		gammaExample{in: Gamma{Shape: 3, Rate: 1}, x: 0.4561868841166705, out: 2.5},
+		gammaExample{in: Gamma{Shape: 5, Rate: 2}, x: 0.970747311923039, out: 5},
+		gammaExample{in: Gamma{Shape: 0.5, Rate: 1}, x: 0.5614219739190001, out: 0.3},
+		gammaExample{
+			in:  Gamma{Shape: 1, Rate: 1},
+			x:   2,
+			err: fmt.Errorf("Quantile not supported for p = 2"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Quantile(ex.x)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if !floatsNanoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Gamma_Float64(t *testing.T) {
+	inputs := []Gamma{
+		// boosted shape
+		Gamma{Shape: 0.1, Rate: 1},
+		Gamma{Shape: 0.5, Rate: 3},
+		Gamma{Shape: 1, Rate: 1},
+		Gamma{Shape: 4.5, Rate: 0.5},
+		Gamma{Shape: 200, Rate: 10},
+	}
+
+	for _, g := range inputs {
+		g.Rand = rand.New(rand.NewSource(1))
+		ed := Empirical{}
+		for i := 0; i < 10001; i++ {
+			v, _ := g.Float64()
+			ed.Add(v)
+		}
+
+		expMean, _ := g.Mean()
+		actual, _ := ed.Mean()
+		if !floatsEqual(actual, expMean, 0.05*expMean) {
+			t.Fatalf("[Mean] expected %v got %v for %#v\n", expMean, actual, g)
+		}
+
+		expVar, _ := g.Variance()
+		actual, _ = ed.Variance()
+		if !floatsEqual(actual, expVar, 0.1*expVar) {
+			t.Fatalf("[Variance] expected %v got %v for %#v\n", expVar, actual, g)
+		}
+	}
+}
+
+// Beta variates can also be constructed as X / (X + Y), where X and Y are
+// Gamma variates with shapes α and β. Both constructions should agree.
+func Test_Gamma_Float64_Beta(t *testing.T) {
+	inputs := []Beta{
+		Beta{Alpha: 0.3, Beta: 0.4},
+		Beta{Alpha: 0.5, Beta: 3},
+		Beta{Alpha: 10, Beta: 3},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, b := range inputs {
+		b.Rand = rnd
+		x, y := Gamma{Shape: b.Alpha, Rate: 1, Rand: rnd}, Gamma{Shape: b.Beta, Rate: 1, Rand: rnd}
+
+		direct, ratio := Empirical{}, Empirical{}
+		for i := 0; i < 10001; i++ {
+			v, _ := b.Float64()
+			direct.Add(v)
+
+			xv, _ := x.Float64()
+			yv, _ := y.Float64()
+			ratio.Add(xv / (xv + yv))
+		}
+
+		dMean, _ := direct.Mean()
+		rMean, _ := ratio.Mean()
+		if !floatsCentiEqual(dMean, rMean) {
+			t.Fatalf("[Mean] expected %v got %v for %#v\n", dMean, rMean, b)
+		}
+
+		dMed, _ := direct.Median()
+		rMed, _ := ratio.Median()
+		if !floatsCentiEqual(dMed, rMed) {
+			t.Fatalf("[Median] expected %v got %v for %#v\n", dMed, rMed, b)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Geometric distribution is a discrete probability distribution of the
+// number of failures before the first success, in a sequence of
+// independent trials which each succeed with probability P ∈ (0, 1].
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Int and Float64. Otherwise the default source in math/rand is used.
+type Geometric struct {
+	P    float64
+	Rand Rand
+}
+
+// Mean returns the mean of the Geometric distribution, i.e., (1 - P) / P.
+func (g Geometric) Mean() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return (1 - g.P) / g.P, nil
+}
+
+// Median returns the median of the Geometric distribution, i.e., the
+// smallest k such that CDF(k) ≥ 0.5.
+func (g Geometric) Median() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	guess, ok := quantileGuess((1 - g.P) / g.P)
+	if !ok {
+		msg := fmt.Sprintf("Median not supported for Geometric Distribution [p = %v]", g.P)
+		return 0, UnsupportedError{S: msg}
+	}
+
+	cdf := func(k int) float64 { return g.cdf(k) }
+	return float64(discreteQuantile(cdf, 0.5, guess)), nil
+}
+
+// Mode returns the mode of the Geometric distribution, which is always
+// zero.
+func (g Geometric) Mode() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// Variance returns the variance of the Geometric distribution, i.e.,
+// (1 - P) / P².
+func (g Geometric) Variance() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return (1 - g.P) / (g.P * g.P), nil
+}
+
+// Skewness returns the skewness of the Geometric distribution, i.e.,
+// (2 - P) / √(1 - P).
+//
+// The skewness is not supported where P = 1, since the distribution is
+// then degenerate.
+func (g Geometric) Skewness() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if g.P == 1 {
+		msg := fmt.Sprintf("Skewness not supported for Geometric Distribution [p = %v]", g.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return (2 - g.P) / math.Sqrt(1-g.P), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Geometric
+// distribution, i.e., 6 + P² / (1 - P).
+//
+// The kurtosis is not supported where P = 1, since the distribution is
+// then degenerate.
+func (g Geometric) ExcessKurtosis() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if g.P == 1 {
+		msg := fmt.Sprintf("ExcessKurtosis not supported for Geometric Distribution [p = %v]", g.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return 6 + g.P*g.P/(1-g.P), nil
+}
+
+// PMF returns the value of the probability mass function of the
+// Geometric distribution at k.
+func (g Geometric) PMF(k int) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if k < 0 {
+		return 0, nil
+	} else if g.P == 1 {
+		if k == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return g.P * math.Exp(float64(k)*math.Log1p(-g.P)), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Geometric distribution at k, i.e., 1 - (1 - P)^(k + 1).
+func (g Geometric) CDF(k int) (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+	return g.cdf(k), nil
+}
+
+func (g Geometric) cdf(k int) float64 {
+	if k < 0 {
+		return 0
+	} else if g.P == 1 {
+		return 1
+	}
+	return -math.Expm1(float64(k+1) * math.Log1p(-g.P))
+}
+
+// Int returns a random variate from the Geometric distribution, using
+// the inversion method.
+//
+// Where P is very small, variates can be too large to be represented as
+// an int, in which case math.MaxInt is returned. Float64 returns such
+// variates exactly.
+func (g Geometric) Int() (int, error) {
+	k, err := g.Float64()
+	return clampInt(k), err
+}
+
+// Float64 returns a random variate from the Geometric distribution. The
+// returned value is always an integer.
+func (g Geometric) Float64() (float64, error) {
+	if ok, err := g.valid(); !ok {
+		return 0, err
+	}
+
+	if g.P == 1 {
+		return 0, nil
+	}
+	// 1 - U is in (0, 1], avoiding log(0).
+	u := 1 - randFloat64(g.Rand)
+	return math.Floor(math.Log(u) / math.Log1p(-g.P)), nil
+}
+
+func (g Geometric) valid() (bool, error) {
+	if !(g.P > 0 && g.P <= 1) {
+		msg := fmt.Sprintf("Invalid Geometric Distribution: [p = %v]", g.P)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
@@ -0,0 +1,106 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_Geometric_Imp_Distribution(t *testing.T) {
+	var _ Distribution = Geometric{}
+	var _ Moments = Geometric{}
+}
+
+func Test_Geometric_Moments(t *testing.T) {
+	g := Geometric{P: 0.2}
+	mean, _ := g.Mean()
+	median, _ := g.Median()
+	mode, _ := g.Mode()
+	variance, _ := g.Variance()
+
+	actual := []float64{mean, median, mode, variance}
+	expected := []float64{4, 3, 0, 20}
+	for i := range actual {
+		if !floatsPicoEqual(actual[i], expected[i]) {
+			t.Fatalf("expected %v\n got %v\n", expected, actual)
+		}
+	}
+}
+
+func Test_Geometric_Skewness_Kurtosis(t *testing.T) {
+	g := Geometric{P: 0.2}
+	skew, _ := g.Skewness()
+	kurtosis, _ := g.ExcessKurtosis()
+	if !floatsPicoEqual(skew, 2.0124611797498106) || !floatsPicoEqual(kurtosis, 6.05) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 2.0124611797498106, 6.05, skew, kurtosis)
+	}
+
+	if _, err := (Geometric{P: 1}).Skewness(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Geometric_Invalid(t *testing.T) {
+	for _, g := range []Geometric{Geometric{P: 0}, Geometric{P: 1.1}} {
+		exp := fmt.Sprintf("Invalid Geometric Distribution: [p = %v]", g.P)
+		fns := []func() (float64, error){g.Mean, g.Median, g.Mode, g.Variance, g.Float64}
+		for _, fn := range fns {
+			if _, err := fn(); err == nil || err.Error() != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, err)
+			}
+		}
+	}
+}
+
+func Test_Geometric_PMF_CDF(t *testing.T) {
+	type Example struct {
+		in       Geometric
+		k        int
+		pmf, cdf float64
+	}
+
+	examples := []Example{
+		Example{in: Geometric{P: 0.2}, k: 3, pmf: 0.1024, cdf: 0.5904},
+		Example{in: Geometric{P: 0.2}, k: 0, pmf: 0.2, cdf: 0.2},
+		Example{in: Geometric{P: 0.2}, k: -1, pmf: 0, cdf: 0},
+		Example{in: Geometric{P: 1}, k: 0, pmf: 1, cdf: 1},
+		Example{in: Geometric{P: 1}, k: 2, pmf: 0, cdf: 1},
+	}
+
+	for _, ex := range examples {
+		pmf, _ := ex.in.PMF(ex.k)
+		cdf, _ := ex.in.CDF(ex.k)
+		if !floatsPicoEqual(pmf, ex.pmf) || !floatsPicoEqual(cdf, ex.cdf) {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", ex.pmf, ex.cdf, pmf, cdf)
+		}
+	}
+}
+
+func Test_Geometric_Int(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, p := range []float64{0.05, 0.3, 0.9, 1} {
+		g := Geometric{P: p, Rand: rnd}
+		checkDiscreteVariates(t, g.Int, g.PMF, 100000, 0.02)
+	}
+}
+
+func Test_Geometric_Int_Large(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := Geometric{P: 1e-300, Rand: rnd}
+	for i := 0; i < 100; i++ {
+		k, err := g.Int()
+		if err != nil || k < 0 {
+			t.Fatalf("expected non-negative variate\n got %v, %v\n", k, err)
+		}
+
+		v, _ := g.Float64()
+		if v < 0 || v != math.Floor(v) {
+			t.Fatalf("expected non-negative integer\n got %v\n", v)
+		}
+	}
+
+	if _, err := g.Median(); err == nil {
+		t.Fatal("expected error for unrepresentable median")
+	}
+}
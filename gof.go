@@ -0,0 +1,409 @@
+package godist
+
+import "math"
+
+const (
+	// largest sample size for which the one-sample Kolmogorov-Smirnov
+	// p-value is calculated exactly.
+	ksExactMax = 100
+
+	// largest product of sample sizes for which the two-sample
+	// Kolmogorov-Smirnov p-value is calculated exactly.
+	ks2ExactMax = 10000
+)
+
+// A ContinuousCDF is a continuous distribution with a cumulative
+// distribution function, against which a sample can be tested.
+//
+// Beta, Normal and Gamma all implement ContinuousCDF.
+type ContinuousCDF interface {
+	CDF(x float64) (float64, error)
+}
+
+// A TestResult is the result of a hypothesis test, i.e., the value of the
+// test statistic, and the probability of a statistic at least as extreme
+// under the null hypothesis.
+//
+// DF is the degrees of freedom of the reference distribution of the
+// statistic, for tests where that applies, and is zero otherwise.
+type TestResult struct {
+	Statistic float64
+	PValue    float64
+	DF        int
+}
+
+// KolmogorovSmirnov carries out a one-sample Kolmogorov-Smirnov test of
+// the null hypothesis that the sample in e was drawn from dist.
+//
+// The statistic is the largest absolute difference between the
+// empirical distribution function of the sample and the CDF of dist.
+// For samples of up to 100 values the p-value is calculated exactly,
+// using the method described by Marsaglia, Tsang and Wang in "Evaluating
+// Kolmogorov's Distribution" (2003). For larger samples the asymptotic
+// Kolmogorov distribution is used, with the finite sample correction
+// described by Stephens in "Use of the Kolmogorov-Smirnov, Cramer-Von
+// Mises and Related Statistics Without Extensive Tables" (1970).
+//
+// The test is not supported on sketched or weighted distributions.
+func KolmogorovSmirnov(e *Empirical, dist ContinuousCDF) (TestResult, error) {
+	if err := validTestSample(e); err != nil {
+		return TestResult{}, err
+	}
+
+	sample := e.sortedSample()
+	n := float64(len(sample))
+
+	var d float64
+	for i, x := range sample {
+		f, err := dist.CDF(x)
+		if err != nil {
+			return TestResult{}, err
+		}
+		d = math.Max(d, math.Max(float64(i+1)/n-f, f-float64(i)/n))
+	}
+
+	var p float64
+	if len(sample) <= ksExactMax {
+		p = 1 - kolmogorovCDF(len(sample), d)
+	} else {
+		sn := math.Sqrt(n)
+		p = kolmogorovQ((sn + 0.12 + 0.11/sn) * d)
+	}
+	return TestResult{Statistic: d, PValue: clampProb(p)}, nil
+}
+
+// KolmogorovSmirnovTwoSample carries out a two-sample Kolmogorov-Smirnov
+// test of the null hypothesis that the samples in e1 and e2 were drawn
+// from the same continuous distribution.
+//
+// The statistic is the largest absolute difference between the
+// empirical distribution functions of the two samples. Where the
+// product of the sample sizes is at most 10000, the p-value is
+// calculated exactly by counting the lattice paths which stay within the
+// statistic, in the same way as R's ks.test. Otherwise the asymptotic
+// Kolmogorov distribution is used, with Stephens' correction for the
+// effective sample size. The exact p-value assumes there are no ties
+// between the samples.
+//
+// The test is not supported on sketched or weighted distributions.
+func KolmogorovSmirnovTwoSample(e1, e2 *Empirical) (TestResult, error) {
+	for _, e := range []*Empirical{e1, e2} {
+		if err := validTestSample(e); err != nil {
+			return TestResult{}, err
+		}
+	}
+
+	x, y := e1.sortedSample(), e2.sortedSample()
+	m, n := float64(len(x)), float64(len(y))
+
+	// walk through both samples in order, stepping over tied values
+	// together so that the statistic is only evaluated between distinct
+	// values.
+	var d float64
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		v := math.Min(x[i], y[j])
+		for i < len(x) && x[i] == v {
+			i++
+		}
+		for j < len(y) && y[j] == v {
+			j++
+		}
+		d = math.Max(d, math.Abs(float64(i)/m-float64(j)/n))
+	}
+
+	var p float64
+	if m*n <= ks2ExactMax {
+		p = 1 - smirnovCDF(len(x), len(y), d)
+	} else {
+		en := math.Sqrt(m * n / (m + n))
+		p = kolmogorovQ((en + 0.12 + 0.11/en) * d)
+	}
+	return TestResult{Statistic: d, PValue: clampProb(p)}, nil
+}
+
+// AndersonDarling carries out a one-sample Anderson-Darling test of the
+// null hypothesis that the sample in e was drawn from dist, where dist is
+// fully specified rather than estimated from the sample.
+//
+// Compared to the Kolmogorov-Smirnov test, the Anderson-Darling test
+// gives more weight to the tails of the distribution. The p-value is
+// calculated as described by Marsaglia and Marsaglia in "Evaluating the
+// Anderson-Darling Distribution" (2004), which corrects the asymptotic
+// distribution of the statistic for the size of the sample.
+//
+// The test is not supported on sketched or weighted distributions.
+func AndersonDarling(e *Empirical, dist ContinuousCDF) (TestResult, error) {
+	if err := validTestSample(e); err != nil {
+		return TestResult{}, err
+	}
+
+	sample := e.sortedSample()
+	n := len(sample)
+	f := make([]float64, n)
+	for i, x := range sample {
+		v, err := dist.CDF(x)
+		if err != nil {
+			return TestResult{}, err
+		}
+		f[i] = v
+	}
+
+	// A² = -n - Σ (2i - 1) [ln F(xᵢ) + ln(1 - F(xₙ₊₁₋ᵢ))] / n
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += float64(2*i+1) * (math.Log(f[i]) + math.Log1p(-f[n-1-i]))
+	}
+	a2 := -float64(n) - sum/float64(n)
+
+	p := 1.0
+	if math.IsInf(a2, 1) {
+		p = 0
+	} else if a2 > 0 {
+		x := adInf(a2)
+		p = 1 - (x + adErrFix(n, x))
+	}
+	return TestResult{Statistic: a2, PValue: clampProb(p)}, nil
+}
+
+// validTestSample returns an error if a goodness-of-fit test cannot be
+// carried out on the sample in e.
+func validTestSample(e *Empirical) error {
+	if e.n == 0 {
+		msg := "test cannot be carried out on empty distribution."
+		return InvalidDistributionError{S: msg}
+	}
+
+	if e.sketch != nil {
+		msg := "test cannot be carried out on a sketched distribution."
+		return UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "test cannot be carried out on a weighted distribution."
+		return UnsupportedError{S: msg}
+	}
+	return nil
+}
+
+// clampProb clamps p to [0, 1], removing any rounding errors which place
+// a probability just outside of that range.
+func clampProb(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
+
+// kolmogorovQ returns the upper tail probability of the asymptotic
+// Kolmogorov distribution, i.e., P(K > λ).
+//
+// The alternating series converges rapidly for large λ, while for small
+// λ the complementary series for P(K ≤ λ) is used instead.
+func kolmogorovQ(lambda float64) float64 {
+	if lambda <= 0 {
+		return 1
+	}
+
+	if lambda < 1.18 {
+		y := -math.Pi * math.Pi / (8 * lambda * lambda)
+		var sum float64
+		for j := 1; j <= 100; j += 2 {
+			term := math.Exp(float64(j*j) * y)
+			sum += term
+			if term < epsilon*sum {
+				break
+			}
+		}
+		return 1 - math.Sqrt(2*math.Pi)/lambda*sum
+	}
+
+	var sum float64
+	sign := 1.0
+	for j := 1; j <= 100; j++ {
+		term := math.Exp(-2 * float64(j*j) * lambda * lambda)
+		sum += sign * term
+		if term < epsilon*sum {
+			break
+		}
+		sign = -sign
+	}
+	return 2 * sum
+}
+
+// kolmogorovCDF returns P(Dₙ < d), where Dₙ is the one-sample
+// Kolmogorov-Smirnov statistic for a sample of size n, using the method
+// described by Marsaglia, Tsang and Wang in "Evaluating Kolmogorov's
+// Distribution" (2003).
+//
+// The probability is an element of the n-th power of an m×m matrix,
+// which is calculated by repeated squaring while tracking a separate
+// decimal exponent to avoid overflow.
+func kolmogorovCDF(n int, d float64) float64 {
+	fn := float64(n)
+	if d <= 0 {
+		return 0
+	} else if d >= 1 {
+		return 1
+	}
+
+	// in the far upper tail, the approximation given by Marsaglia et al.
+	// is accurate to the precision that the complement can be found.
+	s := d * d * fn
+	if s > 7.24 || (s > 3.76 && n > 99) {
+		return 1 - 2*math.Exp(-(2.000071+0.331/math.Sqrt(fn)+1.409/fn)*s)
+	}
+
+	k := int(fn*d) + 1
+	m := 2*k - 1
+	h := float64(k) - fn*d
+
+	hm := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			if i-j+1 >= 0 {
+				hm[i*m+j] = 1
+			}
+		}
+	}
+	for i := 0; i < m; i++ {
+		hm[i*m] -= math.Pow(h, float64(i+1))
+		hm[(m-1)*m+i] -= math.Pow(h, float64(m-i))
+	}
+	if 2*h-1 > 0 {
+		hm[(m-1)*m] += math.Pow(2*h-1, float64(m))
+	}
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			for g := 1; g <= i-j+1; g++ {
+				hm[i*m+j] /= float64(g)
+			}
+		}
+	}
+
+	q, eq := matrixPower(hm, 0, m, n)
+	s = q[(k-1)*m+k-1]
+	for i := 1; i <= n; i++ {
+		s = s * float64(i) / fn
+		if s < 1e-140 {
+			s *= 1e140
+			eq -= 140
+		}
+	}
+	return s * math.Pow(10, float64(eq))
+}
+
+// matrixPower returns the n-th power of the m×m matrix a, scaled by
+// 10^ea, as a matrix and a decimal exponent. The matrix is rescaled
+// whenever its central element grows large, to avoid overflow.
+func matrixPower(a []float64, ea, m, n int) ([]float64, int) {
+	if n == 1 {
+		v := make([]float64, len(a))
+		copy(v, a)
+		return v, ea
+	}
+
+	v, ev := matrixPower(a, ea, m, n/2)
+	b := matrixMultiply(v, v, m)
+	eb := 2 * ev
+	if n%2 == 0 {
+		v, ev = b, eb
+	} else {
+		v, ev = matrixMultiply(a, b, m), ea+eb
+	}
+
+	if v[(m/2)*m+m/2] > 1e140 {
+		for i := range v {
+			v[i] *= 1e-140
+		}
+		ev += 140
+	}
+	return v, ev
+}
+
+// matrixMultiply returns the product of the m×m matrices a and b.
+func matrixMultiply(a, b []float64, m int) []float64 {
+	c := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			var s float64
+			for k := 0; k < m; k++ {
+				s += a[i*m+k] * b[k*m+j]
+			}
+			c[i*m+j] = s
+		}
+	}
+	return c
+}
+
+// smirnovCDF returns P(D < d), where D is the two-sample
+// Kolmogorov-Smirnov statistic for samples of size m and n, assuming
+// there are no ties.
+//
+// Each ordering of the combined samples corresponds to a lattice path
+// from (0, 0) to (m, n), and the probability is the proportion of paths
+// which never stray d or further from the diagonal. The paths are
+// counted by dynamic programming, with each row normalised as it is
+// calculated so that the counts never overflow.
+func smirnovCDF(m, n int, d float64) float64 {
+	if m > n {
+		m, n = n, m
+	}
+	md, nd := float64(m), float64(n)
+
+	// the statistic is a multiple of 1 / mn, so paths are excluded
+	// half-way between the statistic and the next smaller value, which
+	// is robust to rounding error in d.
+	q := (0.5 + math.Floor(d*md*nd-1e-7)) / (md * nd)
+
+	u := make([]float64, n+1)
+	for j := 0; j <= n; j++ {
+		if float64(j)/nd <= q {
+			u[j] = 1
+		}
+	}
+
+	for i := 1; i <= m; i++ {
+		w := float64(i) / float64(i+n)
+		if float64(i)/md > q {
+			u[0] = 0
+		} else {
+			u[0] *= w
+		}
+
+		for j := 1; j <= n; j++ {
+			if math.Abs(float64(i)/md-float64(j)/nd) > q {
+				u[j] = 0
+			} else {
+				u[j] = w*u[j] + u[j-1]
+			}
+		}
+	}
+	return u[n]
+}
+
+// adInf returns the asymptotic distribution function of the
+// Anderson-Darling statistic, as approximated by Marsaglia and Marsaglia
+// (2004).
+func adInf(z float64) float64 {
+	if z < 2 {
+		return math.Exp(-1.2337141/z) / math.Sqrt(z) *
+			(2.00012 + (0.247105-(0.0649821-(0.0347962-(0.011672-0.00168691*z)*z)*z)*z)*z)
+	}
+	return math.Exp(-math.Exp(1.0776 - (2.30695-(0.43424-(0.082433-(0.008056-0.0003146*z)*z)*z)*z)*z))
+}
+
+// adErrFix returns the correction to the asymptotic distribution
+// function x = adInf(z) for a sample of size n, as given by Marsaglia and
+// Marsaglia (2004).
+func adErrFix(n int, x float64) float64 {
+	fn := float64(n)
+	if x > 0.8 {
+		return (-130.2137 + (745.2337-(1705.091-(1950.646-(1116.360-255.7844*x)*x)*x)*x)*x) / fn
+	}
+
+	c := 0.01265 + 0.1757/fn
+	if x < c {
+		t := x / c
+		t = math.Sqrt(t) * (1 - t) * (49*t - 102)
+		return t * (0.0037/(fn*fn) + 0.00078/fn + 0.00006) / fn
+	}
+
+	t := (x - c) / (0.8 - c)
+	t = -0.00022633 + (6.54034-(14.6538-(14.458-(8.259-1.91864*t)*t)*t)*t)*t
+	return t * (0.04213 + 0.01365/fn) / fn
+}
@@ -0,0 +1,268 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_KolmogorovSmirnov(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, n := range []int{20, 2000} {
+		e := &Empirical{}
+		b := Beta{Alpha: 2, Beta: 5, Rand: rnd}
+		for i := 0; i < n; i++ {
+			v, _ := b.Float64()
+			e.Add(v)
+		}
+
+		res, err := KolmogorovSmirnov(e, Beta{Alpha: 2, Beta: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 0.01 {
+			t.Fatalf("expected p-value above %v\n got %v for n = %v\n", 0.01, res.PValue, n)
+		}
+
+		res, err = KolmogorovSmirnov(e, Beta{Alpha: 5, Beta: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue > 1e-6 {
+			t.Fatalf("expected p-value below %v\n got %v for n = %v\n", 1e-6, res.PValue, n)
+		}
+	}
+
+	// a single value x from a uniform distribution has the statistic
+	// max(x, 1-x), and P(D ≥ d) = 2(1 - d).
+	e := &Empirical{}
+	e.Add(0.3)
+	res, err := KolmogorovSmirnov(e, Beta{Alpha: 1, Beta: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsPicoEqual(res.Statistic, 0.7) || !floatsPicoEqual(res.PValue, 0.6) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 0.7, 0.6, res.Statistic, res.PValue)
+	}
+
+	if _, err := KolmogorovSmirnov(&Empirical{}, Normal{Mu: 0, Sigma: 1}); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2, 3)
+	if _, err := KolmogorovSmirnov(sk, Normal{Mu: 0, Sigma: 1}); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+
+	e.Add(0.5)
+	if _, err := KolmogorovSmirnov(e, Beta{}); err == nil {
+		t.Fatal("expected error on invalid distribution")
+	}
+}
+
+func Test_kolmogorovCDF(t *testing.T) {
+	// value given by Marsaglia, Tsang and Wang (2003).
+	if actual := kolmogorovCDF(10, 0.274); !floatsPicoEqual(actual, 0.6284796154565043) {
+		t.Fatalf("expected %v\n got %v\n", 0.6284796154565043, actual)
+	}
+
+	// the exact distribution approaches the asymptotic distribution for
+	// large samples.
+	for _, d := range []float64{0.05, 0.1, 0.15} {
+		sn := 10.0
+		expected := 1 - kolmogorovQ((sn+0.12+0.11/sn)*d)
+		if actual := kolmogorovCDF(100, d); !floatsCentiEqual(actual, expected) {
+			t.Fatalf("expected %v\n got %v for d = %v\n", expected, actual, d)
+		}
+	}
+}
+
+func Test_kolmogorovQ(t *testing.T) {
+	type Example struct {
+		in, out float64
+	}
+
+	// well known critical values of the Kolmogorov distribution.
+	examples := []Example{
+		Example{in: 0, out: 1},
+		Example{in: 1.2238, out: 0.1},
+		Example{in: 1.3581, out: 0.05},
+		Example{in: 1.6276, out: 0.01},
+	}
+
+	for _, ex := range examples {
+		if actual := kolmogorovQ(ex.in); !floatsEqual(actual, ex.out, 1e-4) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+
+	// both series must agree where they meet.
+	if lo, hi := kolmogorovQ(math.Nextafter(1.18, 0)), kolmogorovQ(1.18); !floatsPicoEqual(lo, hi) {
+		t.Fatalf("expected %v\n got %v\n", lo, hi)
+	}
+}
+
+func Test_KolmogorovSmirnovTwoSample(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, n := range []int{30, 500} {
+		e1, e2, e3 := &Empirical{}, &Empirical{}, &Empirical{}
+		n1 := Normal{Mu: 0, Sigma: 1, Rand: rnd}
+		n2 := Normal{Mu: 1.5, Sigma: 1, Rand: rnd}
+		for i := 0; i < n; i++ {
+			v1, _ := n1.Float64()
+			v2, _ := n1.Float64()
+			v3, _ := n2.Float64()
+			e1.Add(v1)
+			e2.Add(v2)
+			e3.Add(v3)
+		}
+
+		res, err := KolmogorovSmirnovTwoSample(e1, e2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 0.01 {
+			t.Fatalf("expected p-value above %v\n got %v for n = %v\n", 0.01, res.PValue, n)
+		}
+
+		res, err = KolmogorovSmirnovTwoSample(e1, e3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue > 1e-3 {
+			t.Fatalf("expected p-value below %v\n got %v for n = %v\n", 1e-3, res.PValue, n)
+		}
+	}
+
+	e1, e2 := &Empirical{}, &Empirical{}
+	e1.Add(1, 2, 3)
+	e2.Add(4, 5, 6, 7)
+	res, err := KolmogorovSmirnovTwoSample(e1, e2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the samples are completely separated, which happens in 2 of the
+	// C(7, 3) = 35 equally likely orderings.
+	if res.Statistic != 1 || !floatsPicoEqual(res.PValue, 2.0/35) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 1, 2.0/35, res.Statistic, res.PValue)
+	}
+
+	if _, err := KolmogorovSmirnovTwoSample(e1, &Empirical{}); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+}
+
+func Test_smirnovCDF(t *testing.T) {
+	// compare against the proportion of all orderings of the combined
+	// samples with a statistic less than d.
+	for _, mn := range [][2]int{{3, 4}, {5, 5}, {2, 7}} {
+		m, n := mn[0], mn[1]
+		var stats []float64
+		for mask := 0; mask < 1<<uint(m+n); mask++ {
+			var i, j, d float64
+			count := 0
+			for b := 0; b < m+n; b++ {
+				if mask&(1<<uint(b)) != 0 {
+					i++
+					count++
+				} else {
+					j++
+				}
+				d = math.Max(d, math.Abs(i/float64(m)-j/float64(n)))
+			}
+			if count == m {
+				stats = append(stats, d)
+			}
+		}
+
+		for _, d := range stats {
+			var less float64
+			for _, s := range stats {
+				if s < d-1e-9 {
+					less++
+				}
+			}
+
+			expected := less / float64(len(stats))
+			if actual := smirnovCDF(m, n, d); !floatsPicoEqual(actual, expected) {
+				t.Fatalf("expected %v\n got %v for m = %v, n = %v, d = %v\n", expected, actual, m, n, d)
+			}
+		}
+	}
+}
+
+func Test_AndersonDarling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, n := range []int{20, 2000} {
+		e := &Empirical{}
+		g := Gamma{Shape: 3, Rate: 2, Rand: rnd}
+		for i := 0; i < n; i++ {
+			v, _ := g.Float64()
+			e.Add(v)
+		}
+
+		res, err := AndersonDarling(e, Gamma{Shape: 3, Rate: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 0.01 {
+			t.Fatalf("expected p-value above %v\n got %v for n = %v\n", 0.01, res.PValue, n)
+		}
+
+		res, err = AndersonDarling(e, Gamma{Shape: 3, Rate: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue > 1e-3 {
+			t.Fatalf("expected p-value below %v\n got %v for n = %v\n", 1e-3, res.PValue, n)
+		}
+	}
+
+	// A² for a single value x from a uniform distribution is
+	// -1 - ln(x) - ln(1 - x).
+	e := &Empirical{}
+	e.Add(0.3)
+	res, err := AndersonDarling(e, Beta{Alpha: 1, Beta: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := -1 - math.Log(0.3) - math.Log(0.7); !floatsPicoEqual(res.Statistic, expected) {
+		t.Fatalf("expected %v\n got %v\n", expected, res.Statistic)
+	}
+
+	// values outside of the support of the distribution.
+	e.Add(2)
+	res, err = AndersonDarling(e, Beta{Alpha: 1, Beta: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(res.Statistic, 1) || res.PValue != 0 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", math.Inf(1), 0, res.Statistic, res.PValue)
+	}
+
+	if _, err := AndersonDarling(&Empirical{}, Normal{Mu: 0, Sigma: 1}); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+}
+
+func Test_adInf(t *testing.T) {
+	type Example struct {
+		in, out float64
+	}
+
+	// well known asymptotic critical values of the Anderson-Darling
+	// statistic.
+	examples := []Example{
+		Example{in: 1.933, out: 0.9},
+		Example{in: 2.492, out: 0.95},
+		Example{in: 3.857, out: 0.99},
+	}
+
+	for _, ex := range examples {
+		if actual := adInf(ex.in); !floatsMilliEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
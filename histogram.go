@@ -0,0 +1,402 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A BinningRule determines how the bins of a Histogram are chosen from
+// its sample.
+type BinningRule int
+
+const (
+	// FixedWidthBins divides the range of the sample into the requested
+	// number of bins of equal width.
+	FixedWidthBins BinningRule = iota
+
+	// SturgesBins divides the range of the sample into ⌈log₂ n⌉ + 1 bins
+	// of equal width, following Sturges' rule, which is suitable for
+	// small, roughly Normal samples.
+	SturgesBins
+
+	// FreedmanDiaconisBins uses bins of width 2 IQR n^(-1/3), following
+	// the rule of Freedman and Diaconis, which is robust to outliers.
+	// Where the IQR is zero, or the rule would use more bins than there
+	// are values, Sturges' rule is used instead.
+	FreedmanDiaconisBins
+
+	// ScottBins uses bins of width 3.49 σ n^(-1/3), following Scott's
+	// rule, which is optimal for Normally distributed samples. As with
+	// FreedmanDiaconisBins, Sturges' rule is used where this would give
+	// more bins than there are values.
+	ScottBins
+
+	// QuantileBins divides the sample into the requested number of bins
+	// containing roughly equal numbers of values, with edges at the
+	// sample quantiles. Bins which would have zero width, because of tied
+	// values, are merged with their neighbours.
+	QuantileBins
+)
+
+// A Histogram summarises a sample by counting the number of values which
+// fall into each of a sequence of contiguous bins.
+//
+// Each bin includes its lower edge but not its upper edge, other than the
+// last bin, which includes both. As a Distribution, a Histogram is
+// piecewise-uniform, i.e., the values in each bin are treated as being
+// spread uniformly across it.
+//
+// A Histogram should be created with NewHistogram or NewHistogramEdges.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type Histogram struct {
+	Rand Rand
+
+	edges  []float64
+	counts []int
+	cum    []int
+}
+
+// NewHistogram returns a histogram of the sample in e, with bins chosen
+// by rule. The number of bins is only used by the FixedWidthBins and
+// QuantileBins rules, and is otherwise ignored.
+//
+// NewHistogram is not supported on sketched or weighted distributions.
+func NewHistogram(e *Empirical, rule BinningRule, bins int) (*Histogram, error) {
+	if ok, err := validHistogramSample(e); !ok {
+		return nil, err
+	}
+
+	sample := e.sortedSample()
+	lo, hi := sample[0], sample[len(sample)-1]
+	if lo == hi {
+		msg := "histogram cannot be created from a sample with zero range."
+		return nil, UnsupportedError{S: msg}
+	}
+
+	n := e.n
+	sturges := int(math.Ceil(math.Log2(n))) + 1
+
+	var width float64
+	switch rule {
+	case FixedWidthBins, QuantileBins:
+		if bins < 1 {
+			msg := fmt.Sprintf("histogram not supported for %v bins.", bins)
+			return nil, UnsupportedError{S: msg}
+		}
+	case SturgesBins:
+		bins = sturges
+	case FreedmanDiaconisBins:
+		iqr, _ := e.IQR()
+		width = 2 * iqr * math.Pow(n, -1.0/3)
+	case ScottBins:
+		var sd float64
+		if n > 1 {
+			sd = math.Sqrt(e.m2 / (n - 1))
+		}
+		width = 3.49 * sd * math.Pow(n, -1.0/3)
+	default:
+		msg := fmt.Sprintf("binning rule %v not supported.", rule)
+		return nil, UnsupportedError{S: msg}
+	}
+
+	// the number of bins is limited to the size of the sample, which a
+	// narrow width and a distant outlier could otherwise greatly exceed.
+	if rule == FreedmanDiaconisBins || rule == ScottBins {
+		bins = sturges
+		if width > 0 && (hi-lo)/width <= n {
+			bins = int(math.Ceil((hi - lo) / width))
+		}
+	}
+
+	edges := make([]float64, bins+1)
+	if rule == QuantileBins {
+		for i := range edges {
+			edges[i] = hyndmanFan(sample, float64(i)/float64(bins), 7)
+		}
+		edges = uniqueSorted(edges)
+	} else {
+		for i := range edges {
+			edges[i] = lo + (hi-lo)*float64(i)/float64(bins)
+		}
+		edges[bins] = hi
+	}
+	return newHistogram(sample, edges), nil
+}
+
+// NewHistogramEdges returns a histogram of the sample in e, using bins
+// with the provided edges, which must be strictly increasing. Values in
+// the sample which lie outside of the edges are not counted, and so the
+// histogram is a distribution of the counted values only.
+//
+// NewHistogramEdges is not supported on sketched or weighted
+// distributions.
+func NewHistogramEdges(e *Empirical, edges []float64) (*Histogram, error) {
+	if ok, err := validHistogramSample(e); !ok {
+		return nil, err
+	}
+
+	if len(edges) < 2 {
+		msg := "histogram cannot be created with fewer than two edges."
+		return nil, UnsupportedError{S: msg}
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i] > edges[i-1]) {
+			msg := fmt.Sprintf("histogram edges must be strictly increasing, got %v.", edges)
+			return nil, UnsupportedError{S: msg}
+		}
+	}
+
+	cp := make([]float64, len(edges))
+	copy(cp, edges)
+	h := newHistogram(e.sortedSample(), cp)
+	if h.total() == 0 {
+		msg := "histogram cannot be created when no values lie within the edges."
+		return nil, InvalidDistributionError{S: msg}
+	}
+	return h, nil
+}
+
+// validHistogramSample returns an error if a histogram cannot be created
+// from the sample in e.
+func validHistogramSample(e *Empirical) (bool, error) {
+	if e.n == 0 {
+		msg := "histogram cannot be created from empty distribution."
+		return false, InvalidDistributionError{S: msg}
+	} else if e.sketch != nil {
+		msg := "histogram cannot be created from a sketched distribution."
+		return false, UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "histogram cannot be created from a weighted distribution."
+		return false, UnsupportedError{S: msg}
+	}
+	return true, nil
+}
+
+// newHistogram returns a histogram of the sorted sample with the provided
+// edges.
+func newHistogram(sorted, edges []float64) *Histogram {
+	bins := len(edges) - 1
+	h := &Histogram{edges: edges, counts: make([]int, bins), cum: make([]int, bins)}
+
+	// the number of values below each edge, where the last edge is
+	// inclusive.
+	below := func(i int) int {
+		if i == bins {
+			return sort.Search(len(sorted), func(j int) bool { return sorted[j] > edges[i] })
+		}
+		return sort.SearchFloat64s(sorted, edges[i])
+	}
+
+	prev := below(0)
+	for i := 0; i < bins; i++ {
+		next := below(i + 1)
+		h.counts[i] = next - prev
+		h.cum[i] = next - below(0)
+		prev = next
+	}
+	return h
+}
+
+// uniqueSorted returns the sorted values x with duplicates removed.
+func uniqueSorted(x []float64) []float64 {
+	out := x[:1]
+	for _, v := range x[1:] {
+		if v != out[len(out)-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Edges returns the edges of the bins of the histogram, such that the
+// i-th bin spans Edges()[i] to Edges()[i+1].
+func (h *Histogram) Edges() []float64 {
+	out := make([]float64, len(h.edges))
+	copy(out, h.edges)
+	return out
+}
+
+// Counts returns the number of values in each bin of the histogram.
+func (h *Histogram) Counts() []int {
+	out := make([]int, len(h.counts))
+	copy(out, h.counts)
+	return out
+}
+
+// CumulativeCounts returns the number of values in each bin of the
+// histogram and all of the bins below it.
+func (h *Histogram) CumulativeCounts() []int {
+	out := make([]int, len(h.cum))
+	copy(out, h.cum)
+	return out
+}
+
+// Densities returns the density of each bin of the histogram, i.e., the
+// proportion of values in the bin divided by its width, so that the
+// histogram has unit area.
+func (h *Histogram) Densities() []float64 {
+	n := float64(h.total())
+	out := make([]float64, len(h.counts))
+	for i, c := range h.counts {
+		out[i] = float64(c) / (n * (h.edges[i+1] - h.edges[i]))
+	}
+	return out
+}
+
+// Mean returns the mean of the histogram, i.e., the mean of the
+// midpoints of the bins, weighted by their counts.
+func (h *Histogram) Mean() (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	var sum float64
+	for i, c := range h.counts {
+		sum += float64(c) * (h.edges[i] + h.edges[i+1]) / 2
+	}
+	return sum / float64(h.total()), nil
+}
+
+// Median returns the median of the histogram, i.e., the 0.5-quantile.
+func (h *Histogram) Median() (float64, error) {
+	return h.Quantile(0.5)
+}
+
+// Mode returns the mode of the histogram, i.e., the midpoint of the bin
+// with the highest density. Where several bins have the highest density,
+// the lowest is used.
+func (h *Histogram) Mode() (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	best := 0
+	densities := h.Densities()
+	for i, d := range densities {
+		if d > densities[best] {
+			best = i
+		}
+	}
+	return (h.edges[best] + h.edges[best+1]) / 2, nil
+}
+
+// Variance returns the variance of the histogram, which includes the
+// variance of the values spread uniformly across each bin.
+func (h *Histogram) Variance() (float64, error) {
+	mean, err := h.Mean()
+	if err != nil {
+		return 0, err
+	}
+
+	// E[X²] of a Uniform distribution on [a, b] is (a² + ab + b²) / 3.
+	var sum float64
+	for i, c := range h.counts {
+		a, b := h.edges[i], h.edges[i+1]
+		sum += float64(c) * (a*a + a*b + b*b) / 3
+	}
+	return math.Max(0, sum/float64(h.total())-mean*mean), nil
+}
+
+// PDF returns the value of the probability density function of the
+// histogram at x.
+func (h *Histogram) PDF(x float64) (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	i := h.bin(x)
+	if i < 0 {
+		return 0, nil
+	}
+	return float64(h.counts[i]) / (float64(h.total()) * (h.edges[i+1] - h.edges[i])), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// histogram at x.
+func (h *Histogram) CDF(x float64) (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	if x < h.edges[0] {
+		return 0, nil
+	} else if x >= h.edges[len(h.edges)-1] {
+		return 1, nil
+	}
+
+	i := h.bin(x)
+	a, b := h.edges[i], h.edges[i+1]
+	below := float64(h.cum[i] - h.counts[i])
+	return (below + float64(h.counts[i])*(x-a)/(b-a)) / float64(h.total()), nil
+}
+
+// Quantile returns the p-quantile of the histogram, i.e., the smallest
+// value x such that CDF(x) = p.
+func (h *Histogram) Quantile(p float64) (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return 0, UnsupportedError{S: msg}
+	}
+	return h.quantile(p * float64(h.total())), nil
+}
+
+// Float64 returns a random variate from the histogram, by choosing a bin
+// with probability proportional to its count, and drawing a value
+// uniformly at random from within it.
+func (h *Histogram) Float64() (float64, error) {
+	if ok, err := h.valid(); !ok {
+		return 0, err
+	}
+
+	i := sort.SearchInts(h.cum, randIntn(h.Rand, h.total())+1)
+	a, b := h.edges[i], h.edges[i+1]
+	return a + (b-a)*randFloat64(h.Rand), nil
+}
+
+// quantile returns the value below which the histogram has the provided
+// count, which must lie within [0, total].
+func (h *Histogram) quantile(count float64) float64 {
+	// the first non-empty bin which reaches the count.
+	i := sort.Search(len(h.cum), func(i int) bool { return float64(h.cum[i]) >= count })
+	for h.counts[i] == 0 {
+		i++
+	}
+	a, b := h.edges[i], h.edges[i+1]
+	below := float64(h.cum[i] - h.counts[i])
+	return a + (b-a)*(count-below)/float64(h.counts[i])
+}
+
+// bin returns the index of the bin containing x, or -1 if x lies outside
+// of the histogram.
+func (h *Histogram) bin(x float64) int {
+	last := len(h.edges) - 1
+	if !(x >= h.edges[0] && x <= h.edges[last]) {
+		return -1
+	} else if x == h.edges[last] {
+		return last - 1
+	}
+	return sort.Search(last, func(i int) bool { return h.edges[i+1] > x })
+}
+
+// total returns the number of values counted by the histogram.
+func (h *Histogram) total() int {
+	if len(h.cum) == 0 {
+		return 0
+	}
+	return h.cum[len(h.cum)-1]
+}
+
+func (h *Histogram) valid() (bool, error) {
+	if h.total() == 0 {
+		msg := fmt.Sprintf("Invalid Histogram: [bins = %v, n = %v]", len(h.counts), h.total())
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
@@ -0,0 +1,219 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_Histogram_Imp_Distribution(t *testing.T) {
+	var _ Distribution = &Histogram{}
+	var _ ContinuousCDF = &Histogram{}
+}
+
+func Test_NewHistogram(t *testing.T) {
+	e := &Empirical{}
+	e.Add(1, 2, 2, 3, 3, 3, 4, 4, 4, 4, 10)
+
+	type Example struct {
+		Rule   BinningRule
+		Bins   int
+		Edges  []float64
+		Counts []int
+	}
+
+	examples := []Example{
+		{Rule: FixedWidthBins, Bins: 3, Edges: []float64{1, 4, 7, 10}, Counts: []int{6, 4, 1}},
+		// ⌈log₂ 11⌉ + 1 = 5 bins.
+		{Rule: SturgesBins, Edges: []float64{1, 2.8, 4.6, 6.4, 8.2, 10}, Counts: []int{3, 7, 0, 0, 1}},
+		// IQR = 1.5, so the width is 3 / 11^(1/3) = 1.3382, giving 7 bins.
+		{Rule: FreedmanDiaconisBins, Edges: []float64{1, 1 + 9.0/7, 1 + 18.0/7, 1 + 27.0/7, 1 + 36.0/7, 1 + 45.0/7, 1 + 54.0/7, 10}, Counts: []int{3, 3, 4, 0, 0, 0, 1}},
+		// quintiles at 2, 3, 4 and 4, with the duplicate edge removed.
+		{Rule: QuantileBins, Bins: 5, Edges: []float64{1, 2, 3, 4, 10}, Counts: []int{1, 2, 3, 5}},
+	}
+
+	for i, ex := range examples {
+		h, err := NewHistogram(e, ex.Rule, ex.Bins)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		edges, counts := h.Edges(), h.Counts()
+		if len(edges) != len(ex.Edges) || len(counts) != len(ex.Counts) {
+			t.Fatalf("[%d] expected %v, %v\n got %v, %v\n", i, ex.Edges, ex.Counts, edges, counts)
+		}
+		for j := range ex.Counts {
+			if !floatsPicoEqual(edges[j+1], ex.Edges[j+1]) || counts[j] != ex.Counts[j] {
+				t.Fatalf("[%d] expected %v, %v\n got %v, %v\n", i, ex.Edges, ex.Counts, edges, counts)
+			}
+		}
+	}
+
+	// Scott's rule uses 3.49 σ n^(-1/3), with the unbiased σ.
+	variance, _ := e.Variance()
+	width := 3.49 * math.Sqrt(variance*11/10) * math.Pow(11, -1.0/3)
+	h, err := NewHistogram(e, ScottBins, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := int(math.Ceil(9 / width)); len(h.Counts()) != expected {
+		t.Fatalf("expected %v\n got %v\n", expected, len(h.Counts()))
+	}
+
+	// a distant outlier would give the Freedman-Diaconis rule far more
+	// bins than values, so Sturges' rule is used instead, with
+	// ⌈log₂ 1001⌉ + 1 = 11 bins. The outlier inflates σ, and so the width
+	// of Scott's rule.
+	outlier := &Empirical{}
+	for i := 0; i < 1000; i++ {
+		outlier.Add(float64(i) / 1000)
+	}
+	outlier.Add(1e9)
+	for _, rule := range []BinningRule{FreedmanDiaconisBins, ScottBins} {
+		h, err := NewHistogram(outlier, rule, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(h.Counts()); n > 1001 || (rule == FreedmanDiaconisBins && n != 11) {
+			t.Fatalf("expected %v\n got %v for rule %v\n", 11, n, rule)
+		}
+	}
+}
+
+func Test_NewHistogram_Errors(t *testing.T) {
+	if _, err := NewHistogram(&Empirical{}, SturgesBins, 0); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2, 3)
+	if _, err := NewHistogram(sk, SturgesBins, 0); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+
+	e := &Empirical{}
+	e.Add(2, 2)
+	if _, err := NewHistogram(e, SturgesBins, 0); err == nil {
+		t.Fatal("expected error on zero range")
+	}
+
+	e.Add(3)
+	if _, err := NewHistogram(e, FixedWidthBins, 0); err == nil {
+		t.Fatal("expected error on zero bins")
+	}
+	if _, err := NewHistogram(e, BinningRule(9), 0); err == nil {
+		t.Fatal("expected error on invalid binning rule")
+	}
+
+	for _, edges := range [][]float64{{1}, {1, 3, 3}, {5, 6}} {
+		if _, err := NewHistogramEdges(e, edges); err == nil {
+			t.Fatalf("expected error for edges %v\n", edges)
+		}
+	}
+
+	if _, err := (&Histogram{}).Mean(); err == nil {
+		t.Fatal("expected error on invalid histogram")
+	}
+}
+
+func Test_Histogram(t *testing.T) {
+	e := &Empirical{}
+	e.Add(-1, 0.5, 1, 1.5, 2.5, 3, 3.5, 9)
+
+	// the values outside of the edges are not counted.
+	h, err := NewHistogramEdges(e, []float64{0, 1, 2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts, cum, dens := h.Counts(), h.CumulativeCounts(), h.Densities()
+	eCounts, eCum, eDens := []int{1, 2, 3}, []int{1, 3, 6}, []float64{1.0 / 6, 2.0 / 6, 3.0 / 12}
+	for i := range eCounts {
+		if counts[i] != eCounts[i] || cum[i] != eCum[i] || !floatsPicoEqual(dens[i], eDens[i]) {
+			t.Fatalf("expected %v, %v, %v\n got %v, %v, %v\n", eCounts, eCum, eDens, counts, cum, dens)
+		}
+	}
+
+	// midpoints 0.5, 1.5 and 3, weighted by 1, 2 and 3.
+	mean, _ := h.Mean()
+	variance, _ := h.Variance()
+	mode, _ := h.Mode()
+	median, _ := h.Median()
+	expMean := (0.5 + 2*1.5 + 3*3) / 6
+	expVariance := (1.0/3+2*7.0/3+3*28.0/3)/6 - expMean*expMean
+	if !floatsPicoEqual(mean, expMean) || !floatsPicoEqual(variance, expVariance) || mode != 1.5 || !floatsPicoEqual(median, 2) {
+		t.Fatalf("expected %v, %v, %v, %v\n got %v, %v, %v, %v\n", expMean, expVariance, 1.5, 2, mean, variance, mode, median)
+	}
+
+	type Example struct {
+		X, PDF, CDF float64
+	}
+	examples := []Example{
+		{X: -0.5, PDF: 0, CDF: 0},
+		{X: 0, PDF: 1.0 / 6, CDF: 0},
+		{X: 1.5, PDF: 2.0 / 6, CDF: 2.0 / 6},
+		{X: 3, PDF: 3.0 / 12, CDF: 0.75},
+		{X: 4, PDF: 3.0 / 12, CDF: 1},
+		{X: 5, PDF: 0, CDF: 1},
+	}
+	for _, ex := range examples {
+		pdf, _ := h.PDF(ex.X)
+		cdf, _ := h.CDF(ex.X)
+		if !floatsPicoEqual(pdf, ex.PDF) || !floatsPicoEqual(cdf, ex.CDF) {
+			t.Fatalf("expected %v, %v\n got %v, %v at %v\n", ex.PDF, ex.CDF, pdf, cdf, ex.X)
+		}
+
+		if ex.CDF > 0 && ex.CDF < 1 {
+			if q, _ := h.Quantile(ex.CDF); !floatsPicoEqual(q, ex.X) {
+				t.Fatalf("expected %v\n got %v\n", ex.X, q)
+			}
+		}
+	}
+
+	if _, err := h.Quantile(-0.1); err == nil {
+		t.Fatal("expected error on invalid probability")
+	}
+
+	// empty bins at the bottom of the histogram are skipped.
+	h, err = NewHistogramEdges(e, []float64{-3, -2, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := h.Quantile(0); q != -2 {
+		t.Fatalf("expected %v\n got %v\n", -2, q)
+	}
+}
+
+func Test_Histogram_Float64(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	n := Normal{Mu: 5, Sigma: 2, Rand: rnd}
+	for i := 0; i < 1000; i++ {
+		v, _ := n.Float64()
+		e.Add(v)
+	}
+
+	h, err := NewHistogram(e, FreedmanDiaconisBins, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Rand = rnd
+
+	samples := &Empirical{}
+	edges := h.Edges()
+	for i := 0; i < 20000; i++ {
+		v, _ := h.Float64()
+		if v < edges[0] || v > edges[len(edges)-1] {
+			t.Fatalf("expected value in [%v, %v]\n got %v\n", edges[0], edges[len(edges)-1], v)
+		}
+		samples.Add(v)
+	}
+
+	mean, _ := h.Mean()
+	variance, _ := h.Variance()
+	m, _ := samples.Mean()
+	v, _ := samples.Variance()
+	if !floatsDeciEqual(m, mean) || !floatsEqual(v, variance, 0.2) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", mean, variance, m, v)
+	}
+}
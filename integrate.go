@@ -0,0 +1,74 @@
+package godist
+
+import "math"
+
+const (
+	// maximum number of times the step size of tanh-sinh quadrature is
+	// halved.
+	tanhSinhMaxLevel = 10
+
+	// quadrature points beyond this value of t contribute nothing
+	// representable to the integral.
+	tanhSinhMaxT = 6.5
+)
+
+// integrate returns an estimate of the definite integral of f over the
+// finite interval [a, b], accurate to within approximately tol, using
+// tanh-sinh (double exponential) quadrature, as described by Takahasi
+// and Mori in "Double exponential formulas for numerical integration"
+// (1974).
+//
+// f is never evaluated at the end points of the interval, and integrable
+// singularities at a or b, such as 1 / √x at zero, are handled well.
+func integrate(f func(x float64) float64, a, b, tol float64) float64 {
+	if a == b {
+		return 0
+	}
+
+	d := (b - a) / 2
+	h := 1.0
+	sum := math.Pi / 2 * f(a+d)
+	sum += tanhSinhSum(f, a, b, h, 1)
+	est := d * h * sum
+
+	for level := 1; level <= tanhSinhMaxLevel; level++ {
+		// halving the step only requires evaluating the new odd points.
+		h /= 2
+		sum += tanhSinhSum(f, a, b, h, 2)
+		next := d * h * sum
+		if math.Abs(next-est) <= tol {
+			return next
+		}
+		est = next
+	}
+	return est
+}
+
+// tanhSinhSum returns the weighted sum of f at the tanh-sinh quadrature
+// points t = kh, for k = 1, 1 + step, 1 + 2step, ..., taking points
+// symmetrically from both ends of [a, b].
+func tanhSinhSum(f func(float64) float64, a, b, h float64, step int) float64 {
+	d := (b - a) / 2
+	var sum float64
+	for k := 1; float64(k)*h <= tanhSinhMaxT; k += step {
+		t := float64(k) * h
+		u := math.Pi / 2 * math.Sinh(t)
+		cu := math.Cosh(u)
+
+		// distance of the points from each end of the interval, which is
+		// calculated directly to retain precision near the end points.
+		xc := d * math.Exp(-u) / cu
+		w := math.Pi / 2 * math.Cosh(t) / (cu * cu)
+		if xc == 0 || w == 0 {
+			break
+		}
+
+		if x := a + xc; x > a && x < b {
+			sum += w * f(x)
+		}
+		if x := b - xc; x > a && x < b {
+			sum += w * f(x)
+		}
+	}
+	return sum
+}
@@ -0,0 +1,31 @@
+package godist
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_integrate(t *testing.T) {
+	type Example struct {
+		f    func(float64) float64
+		a, b float64
+		out  float64
+	}
+
+	examples := []Example{
+		Example{f: func(x float64) float64 { return x * x }, a: 0, b: 3, out: 9},
+		Example{f: math.Sin, a: 0, b: math.Pi, out: 2},
+		Example{f: math.Exp, a: -1, b: 1, out: math.E - 1/math.E},
+		Example{f: math.Sin, a: 1, b: 1, out: 0},
+		// singular at the lower end point.
+		Example{f: math.Log, a: 0, b: 1, out: -1},
+		Example{f: func(x float64) float64 { return 1 / math.Sqrt(x) }, a: 0, b: 1, out: 2},
+	}
+
+	for _, ex := range examples {
+		actual := integrate(ex.f, ex.a, ex.b, 1e-10)
+		if !floatsEqual(actual, ex.out, 1e-6) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
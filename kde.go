@@ -0,0 +1,555 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// number of points at which the density is evaluated when searching
+	// for the mode of a KDE.
+	kdeModeGrid = 512
+
+	// absolute accuracy requested when numerically integrating the
+	// moments of a bounded KDE.
+	kdeTolerance = 1e-10
+
+	// the difference between 1 and the next representable float64.
+	epsilon = 2.220446049250313e-16
+)
+
+// A Kernel is a symmetric probability density used to smooth each value
+// of a sample in a KDE. Every kernel is scaled to have unit variance, so
+// that the bandwidth of a KDE is the standard deviation of the kernel.
+type Kernel int
+
+const (
+	// GaussianKernel is the standard Normal density.
+	GaussianKernel Kernel = iota
+
+	// EpanechnikovKernel is the parabolic kernel, which is optimal in
+	// terms of mean integrated squared error, and is supported on
+	// [-√5, √5].
+	EpanechnikovKernel
+
+	// UniformKernel is the rectangular kernel, supported on [-√3, √3].
+	UniformKernel
+
+	// TriangularKernel is supported on [-√6, √6].
+	TriangularKernel
+)
+
+// A BandwidthRule selects the bandwidth of a KDE from its sample.
+type BandwidthRule int
+
+const (
+	// SilvermanBandwidth uses Silverman's rule of thumb,
+	// 0.9 min(σ, IQR / 1.34) n^(-1/5), which is robust to heavy tails
+	// and multi-modality.
+	SilvermanBandwidth BandwidthRule = iota
+
+	// ScottBandwidth uses Scott's rule of thumb, 1.06 σ n^(-1/5), which
+	// is optimal for Normally distributed samples.
+	ScottBandwidth
+
+	// PluginBandwidth uses the two-stage direct plug-in rule described by
+	// Wand and Jones in "Kernel Smoothing" (1995), section 3.6, which
+	// estimates the curvature of the density from the sample itself.
+	// Selecting the bandwidth takes time proportional to n².
+	PluginBandwidth
+)
+
+// A KDE is a kernel density estimate, i.e., a smooth continuous
+// distribution formed from a sample by placing a kernel at each value.
+//
+// A KDE should be created with NewKDE or NewUnitKDE, which select a
+// Bandwidth from the sample. Bandwidth may subsequently be changed to
+// any positive value.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type KDE struct {
+	Kernel    Kernel
+	Bandwidth float64
+	Rand      Rand
+
+	sample []float64
+	mean   float64
+	m2     float64
+	unit   bool
+}
+
+// NewKDE returns a kernel density estimate of the sample in e, using the
+// provided kernel, with a bandwidth selected by rule.
+//
+// NewKDE is not supported on sketched or weighted distributions.
+func NewKDE(e *Empirical, kernel Kernel, rule BandwidthRule) (*KDE, error) {
+	if e.n == 0 {
+		msg := "KDE cannot be created from empty distribution."
+		return nil, InvalidDistributionError{S: msg}
+	} else if e.sketch != nil {
+		msg := "KDE cannot be created from a sketched distribution."
+		return nil, UnsupportedError{S: msg}
+	} else if e.tree.weighted {
+		msg := "KDE cannot be created from a weighted distribution."
+		return nil, UnsupportedError{S: msg}
+	}
+	if kernel < GaussianKernel || kernel > TriangularKernel {
+		msg := fmt.Sprintf("kernel %v not supported.", kernel)
+		return nil, UnsupportedError{S: msg}
+	}
+
+	h, err := selectBandwidth(e, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := make([]float64, e.tree.len())
+	copy(sample, e.sortedSample())
+	return &KDE{Kernel: kernel, Bandwidth: h, sample: sample, mean: e.mean, m2: e.m2}, nil
+}
+
+// NewUnitKDE returns a kernel density estimate of the sample in e, in the
+// same way as NewKDE, but corrected for the boundaries of the unit
+// interval, which every value in e must lie within. It is suitable for
+// samples of proportions, such as those modelled by a Beta distribution.
+//
+// The density is corrected by reflecting each kernel in the boundaries
+// at 0 and 1, as described by Schuster in "Incorporating Support
+// Constraints into Nonparametric Estimators of Densities" (1985), so that
+// no probability mass leaks outside of the unit interval. The correction
+// is exact while the bandwidth is small relative to the interval, and
+// otherwise the reflected density is normalised by the mass it retains
+// within the interval.
+func NewUnitKDE(e *Empirical, kernel Kernel, rule BandwidthRule) (*KDE, error) {
+	k, err := NewKDE(e, kernel, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.sample[0] < 0 || k.sample[len(k.sample)-1] > 1 {
+		msg := "unit KDE cannot be created from values outside of [0, 1]."
+		return nil, UnsupportedError{S: msg}
+	}
+	k.unit = true
+	return k, nil
+}
+
+// selectBandwidth returns the bandwidth selected by rule for the sample
+// in e.
+func selectBandwidth(e *Empirical, rule BandwidthRule) (float64, error) {
+	n := e.n
+	var sd float64
+	if n > 1 {
+		sd = math.Sqrt(e.m2 / (n - 1))
+	}
+	iqr, _ := e.IQR()
+
+	// a robust estimate of scale, falling back to the standard deviation
+	// where more than half of the sample is tied.
+	scale := math.Min(sd, iqr/1.34)
+	if scale == 0 {
+		scale = sd
+	}
+	if scale == 0 {
+		msg := "bandwidth cannot be selected for sample with zero variance."
+		return 0, UnsupportedError{S: msg}
+	}
+
+	silverman := 0.9 * scale * math.Pow(n, -0.2)
+	switch rule {
+	case SilvermanBandwidth:
+		return silverman, nil
+	case ScottBandwidth:
+		return 1.06 * sd * math.Pow(n, -0.2), nil
+	case PluginBandwidth:
+		// the estimated curvature can be degenerate for very small
+		// samples, in which case Silverman's rule is used instead.
+		if h := pluginBandwidth(e.sortedSample(), scale); h > 0 && !math.IsInf(h, 1) {
+			return h, nil
+		}
+		return silverman, nil
+	}
+	msg := fmt.Sprintf("bandwidth rule %v not supported.", rule)
+	return 0, UnsupportedError{S: msg}
+}
+
+// pluginBandwidth returns the two-stage direct plug-in bandwidth of the
+// sorted sample x, with the curvature of a Normal density of the provided
+// scale used as the initial reference.
+func pluginBandwidth(x []float64, scale float64) float64 {
+	n := float64(len(x))
+	sqrt2Pi := math.Sqrt(2 * math.Pi)
+
+	// ψ₈ for a Normal reference density.
+	psi8 := 105 / (32 * math.Sqrt(math.Pi) * math.Pow(scale, 9))
+
+	// estimate ψ₆ with a pilot bandwidth chosen for ψ₈.
+	g1 := math.Pow(30/(sqrt2Pi*psi8*n), 1.0/9)
+	psi6 := psiFunctional(x, g1, func(u float64) float64 {
+		u2 := u * u
+		return (u2*u2*u2 - 15*u2*u2 + 45*u2 - 15) * math.Exp(-u2/2) / sqrt2Pi
+	}, 7)
+
+	// estimate ψ₄ with a pilot bandwidth chosen for ψ₆.
+	g2 := math.Pow(-6/(sqrt2Pi*psi6*n), 1.0/7)
+	psi4 := psiFunctional(x, g2, func(u float64) float64 {
+		u2 := u * u
+		return (u2*u2 - 6*u2 + 3) * math.Exp(-u2/2) / sqrt2Pi
+	}, 5)
+
+	// the asymptotically optimal bandwidth for a Gaussian kernel, with
+	// R(K) = 1 / (2√π), and unit variance.
+	return math.Pow(1/(2*math.Sqrt(math.Pi)*psi4*n), 0.2)
+}
+
+// psiFunctional returns the kernel estimate of the density functional
+//
+//	ψ = Σᵢ Σⱼ φ⁽ʳ⁾((xᵢ - xⱼ) / g) / (n² g^(r+1))
+//
+// where deriv is the r-th derivative of the standard Normal density,
+// and pow is r + 1. Pairs further apart than 40g contribute nothing
+// representable, and are skipped using the ordering of x.
+func psiFunctional(x []float64, g float64, deriv func(float64) float64, pow float64) float64 {
+	n := float64(len(x))
+	sum := n * deriv(0)
+	for i := range x {
+		for j := i + 1; j < len(x) && x[j]-x[i] < 40*g; j++ {
+			sum += 2 * deriv((x[i]-x[j])/g)
+		}
+	}
+	return sum / (n * n * math.Pow(g, pow))
+}
+
+// Mean returns the mean of the KDE.
+//
+// For an unbounded KDE this is the sample mean. For a KDE created with
+// NewUnitKDE the mean is found by numerical integration.
+func (k *KDE) Mean() (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+
+	if k.unit {
+		return k.integrate(func(x, f float64) float64 { return x * f }), nil
+	}
+	return k.mean, nil
+}
+
+// Median returns the median of the KDE, i.e., the 0.5-quantile.
+func (k *KDE) Median() (float64, error) {
+	return k.Quantile(0.5)
+}
+
+// Mode returns the mode of the KDE, i.e., the location of the highest
+// density.
+//
+// The density is evaluated on a grid spanning the sample, and the
+// highest point is refined using golden-section search. Where the KDE is
+// multi-modal the mode is found to within the resolution of the grid.
+func (k *KDE) Mode() (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+
+	lo, hi := k.sample[0], k.sample[len(k.sample)-1]
+	if k.unit {
+		lo, hi = 0, 1
+	}
+	if lo == hi {
+		return lo, nil
+	}
+
+	step := (hi - lo) / (kdeModeGrid - 1)
+	best, bestF := lo, k.pdf(lo)
+	for i := 1; i < kdeModeGrid; i++ {
+		x := lo + float64(i)*step
+		if f := k.pdf(x); f > bestF {
+			best, bestF = x, f
+		}
+	}
+
+	// golden-section search for the maximum around the best grid point.
+	a, b := math.Max(lo, best-step), math.Min(hi, best+step)
+	invPhi := (math.Sqrt(5) - 1) / 2
+	c, d := b-invPhi*(b-a), a+invPhi*(b-a)
+	for i := 0; i < 100 && b-a > epsilon*math.Max(1, math.Abs(a)); i++ {
+		if k.pdf(c) > k.pdf(d) {
+			b, d = d, c
+			c = b - invPhi*(b-a)
+		} else {
+			a, c = c, d
+			d = a + invPhi*(b-a)
+		}
+	}
+
+	mode := a + (b-a)/2
+	if k.pdf(mode) < bestF {
+		return best, nil
+	}
+	return mode, nil
+}
+
+// Variance returns the variance of the KDE.
+//
+// For an unbounded KDE this is the sample variance plus the variance of
+// the kernel, i.e., the square of the bandwidth. For a KDE created with
+// NewUnitKDE the variance is found by numerical integration.
+func (k *KDE) Variance() (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+
+	if k.unit {
+		mean, err := k.Mean()
+		if err != nil {
+			return 0, err
+		}
+		return k.integrate(func(x, f float64) float64 { return (x - mean) * (x - mean) * f }), nil
+	}
+	return k.m2/float64(len(k.sample)) + k.Bandwidth*k.Bandwidth, nil
+}
+
+// PDF returns the value of the probability density function of the KDE
+// at x.
+func (k *KDE) PDF(x float64) (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+	return k.pdf(x), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// KDE at x.
+func (k *KDE) CDF(x float64) (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+	return k.cdf(x), nil
+}
+
+// Quantile returns the p-quantile of the KDE, i.e., the value x such
+// that CDF(x) = p, which is found by bisection.
+func (k *KDE) Quantile(p float64) (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return 0, UnsupportedError{S: msg}
+	}
+
+	// the support of the KDE, or far enough into the tails of a Gaussian
+	// kernel that the density is not representable.
+	r := k.Kernel.radius()
+	if math.IsInf(r, 1) {
+		r = 40
+	}
+	lo, hi := k.sample[0]-r*k.Bandwidth, k.sample[len(k.sample)-1]+r*k.Bandwidth
+	if k.unit {
+		lo, hi = 0, 1
+	}
+
+	for i := 0; i < 200 && hi-lo > epsilon*math.Max(1, math.Abs(lo)); i++ {
+		mid := lo + (hi-lo)/2
+		if k.cdf(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2, nil
+}
+
+// Float64 returns a random variate from the KDE, by drawing a value from
+// the sample uniformly at random, and perturbing it by a random variate
+// from the kernel.
+//
+// For a KDE created with NewUnitKDE, perturbations which leave the unit
+// interval are reflected back into it, and any which remain outside of
+// it are redrawn, in the same way as the density is normalised.
+func (k *KDE) Float64() (float64, error) {
+	if ok, err := k.valid(); !ok {
+		return 0, err
+	}
+
+	for {
+		x := k.sample[randIntn(k.Rand, len(k.sample))] + k.Bandwidth*k.Kernel.sample(k.Rand)
+		if !k.unit {
+			return x, nil
+		}
+
+		if x < 0 {
+			x = -x
+		} else if x > 1 {
+			x = 2 - x
+		}
+		if x >= 0 && x <= 1 {
+			return x, nil
+		}
+	}
+}
+
+// pdf returns the density of the KDE at x.
+func (k *KDE) pdf(x float64) float64 {
+	if k.unit && (x < 0 || x > 1) {
+		return 0
+	}
+
+	h := k.Bandwidth
+	var sum float64
+	for _, xi := range k.sample {
+		sum += k.Kernel.pdf((x - xi) / h)
+		if k.unit {
+			sum += k.Kernel.pdf((x+xi)/h) + k.Kernel.pdf((x-2+xi)/h)
+		}
+	}
+	if k.unit {
+		sum /= k.unitMass()
+	}
+	return sum / (float64(len(k.sample)) * h)
+}
+
+// cdf returns the cumulative distribution function of the KDE at x.
+func (k *KDE) cdf(x float64) float64 {
+	if k.unit {
+		if x <= 0 {
+			return 0
+		} else if x >= 1 {
+			return 1
+		}
+	}
+
+	h := k.Bandwidth
+	kc := k.Kernel.cdf
+	var sum float64
+	for _, xi := range k.sample {
+		if k.unit {
+			sum += k.reflectedMass(xi, x)
+		} else {
+			sum += kc((x - xi) / h)
+		}
+	}
+	if k.unit {
+		sum /= k.unitMass()
+	}
+	return math.Max(0, math.Min(1, sum/float64(len(k.sample))))
+}
+
+// reflectedMass returns the mass between 0 and x of the kernel at xi,
+// along with its reflections in the boundaries of the unit interval.
+func (k *KDE) reflectedMass(xi, x float64) float64 {
+	h, kc := k.Bandwidth, k.Kernel.cdf
+	return kc((x-xi)/h) - kc(-xi/h) +
+		kc((x+xi)/h) - kc(xi/h) +
+		kc((x-2+xi)/h) - kc((xi-2)/h)
+}
+
+// unitMass returns the mean mass of the reflected kernels within the
+// unit interval, which is one unless the bandwidth is wide enough for a
+// kernel to extend beyond its reflections.
+func (k *KDE) unitMass() float64 {
+	var sum float64
+	for _, xi := range k.sample {
+		sum += k.reflectedMass(xi, 1)
+	}
+	return sum / float64(len(k.sample))
+}
+
+// integrate returns the integral of g(x, f(x)) over the unit interval,
+// where f is the density of the KDE.
+func (k *KDE) integrate(g func(x, f float64) float64) float64 {
+	return integrate(func(x float64) float64 { return g(x, k.pdf(x)) }, 0, 1, kdeTolerance)
+}
+
+func (k *KDE) valid() (bool, error) {
+	if len(k.sample) == 0 || !(k.Bandwidth > 0) || k.Kernel < GaussianKernel || k.Kernel > TriangularKernel {
+		msg := fmt.Sprintf("Invalid KDE: [n = %v, kernel = %v, bandwidth = %v]", len(k.sample), k.Kernel, k.Bandwidth)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
+
+// radius returns the radius of the support of the kernel, which is
+// infinite for the Gaussian kernel.
+func (kn Kernel) radius() float64 {
+	switch kn {
+	case EpanechnikovKernel:
+		return math.Sqrt(5)
+	case UniformKernel:
+		return math.Sqrt(3)
+	case TriangularKernel:
+		return math.Sqrt(6)
+	}
+	return math.Inf(1)
+}
+
+// pdf returns the density of the kernel at u.
+func (kn Kernel) pdf(u float64) float64 {
+	r := kn.radius()
+	if math.Abs(u) > r {
+		return 0
+	}
+
+	t := u / r
+	switch kn {
+	case EpanechnikovKernel:
+		return 0.75 * (1 - t*t) / r
+	case UniformKernel:
+		return 0.5 / r
+	case TriangularKernel:
+		return (1 - math.Abs(t)) / r
+	}
+	return math.Exp(-u*u/2) / math.Sqrt(2*math.Pi)
+}
+
+// cdf returns the cumulative distribution function of the kernel at u.
+func (kn Kernel) cdf(u float64) float64 {
+	r := kn.radius()
+	if kn == GaussianKernel {
+		return 0.5 * math.Erfc(-u/math.Sqrt2)
+	} else if u <= -r {
+		return 0
+	} else if u >= r {
+		return 1
+	}
+
+	t := u / r
+	switch kn {
+	case EpanechnikovKernel:
+		return 0.5 + 0.75*t - 0.25*t*t*t
+	case UniformKernel:
+		return (t + 1) / 2
+	}
+
+	// triangular
+	if t < 0 {
+		return (1 + t) * (1 + t) / 2
+	}
+	return 1 - (1-t)*(1-t)/2
+}
+
+// sample returns a random variate from the kernel.
+func (kn Kernel) sample(rnd Rand) float64 {
+	r := kn.radius()
+	switch kn {
+	case EpanechnikovKernel:
+		// of three uniform variates on [-1, 1], take the second if the
+		// third is the largest in magnitude, and the third otherwise,
+		// as described by Devroye in "Non-Uniform Random Variate
+		// Generation" (1986).
+		u1 := 2*randFloat64(rnd) - 1
+		u2 := 2*randFloat64(rnd) - 1
+		u3 := 2*randFloat64(rnd) - 1
+		if math.Abs(u3) >= math.Abs(u2) && math.Abs(u3) >= math.Abs(u1) {
+			return r * u2
+		}
+		return r * u3
+	case UniformKernel:
+		return r * (2*randFloat64(rnd) - 1)
+	case TriangularKernel:
+		return r * (randFloat64(rnd) + randFloat64(rnd) - 1)
+	}
+	return genStdNormal(rnd)
+}
@@ -0,0 +1,291 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var kernels = []Kernel{GaussianKernel, EpanechnikovKernel, UniformKernel, TriangularKernel}
+
+func Test_KDE_Imp_Distribution(t *testing.T) {
+	var _ Distribution = &KDE{}
+	var _ ContinuousCDF = &KDE{}
+}
+
+func Test_Kernel(t *testing.T) {
+	for _, kn := range kernels {
+		r := kn.radius()
+		if math.IsInf(r, 1) {
+			r = 40
+		}
+
+		// every kernel is a density with unit variance.
+		mass := 2 * integrate(kn.pdf, 0, r, 1e-12)
+		variance := 2 * integrate(func(u float64) float64 { return u * u * kn.pdf(u) }, 0, r, 1e-12)
+		if !floatsNanoEqual(mass, 1) || !floatsNanoEqual(variance, 1) {
+			t.Fatalf("expected %v, %v\n got %v, %v for kernel %v\n", 1, 1, mass, variance, kn)
+		}
+
+		// integrate either side of zero, where the triangular kernel is
+		// not smooth.
+		for _, u := range []float64{-3, -1.2, -0.5, 0.3, 1, 2.5} {
+			expected := integrate(kn.pdf, -r, math.Min(u, 0), 1e-12)
+			if u > 0 {
+				expected += integrate(kn.pdf, 0, math.Min(u, r), 1e-12)
+			}
+			if actual := kn.cdf(u); !floatsNanoEqual(actual, expected) {
+				t.Fatalf("expected %v\n got %v for kernel %v at %v\n", expected, actual, kn, u)
+			}
+		}
+
+		// the mean and variance of sampled values.
+		rnd := rand.New(rand.NewSource(42))
+		e := &Empirical{}
+		for i := 0; i < 100000; i++ {
+			e.Add(kn.sample(rnd))
+		}
+		m, _ := e.Mean()
+		v, _ := e.Variance()
+		if !floatsCentiEqual(m, 0) || !floatsEqual(v, 1, 0.02) {
+			t.Fatalf("expected %v, %v\n got %v, %v for kernel %v\n", 0, 1, m, v, kn)
+		}
+	}
+}
+
+func Test_NewKDE_Bandwidth(t *testing.T) {
+	e := &Empirical{}
+	e.Add(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	// σ = 3.0277, IQR / 1.34 = 3.3582.
+	sd := math.Sqrt(55.0 / 6)
+	examples := map[BandwidthRule]float64{
+		SilvermanBandwidth: 0.9 * sd * math.Pow(10, -0.2),
+		ScottBandwidth:     1.06 * sd * math.Pow(10, -0.2),
+	}
+
+	for rule, expected := range examples {
+		k, err := NewKDE(e, GaussianKernel, rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !floatsPicoEqual(k.Bandwidth, expected) {
+			t.Fatalf("expected %v\n got %v for rule %v\n", expected, k.Bandwidth, rule)
+		}
+	}
+
+	// for a Normal sample the plug-in bandwidth should be close to the
+	// optimal bandwidth, 1.06 σ n^(-1/5).
+	rnd := rand.New(rand.NewSource(42))
+	e = &Empirical{}
+	n := Normal{Mu: 0, Sigma: 2, Rand: rnd}
+	for i := 0; i < 2000; i++ {
+		v, _ := n.Float64()
+		e.Add(v)
+	}
+
+	k, err := NewKDE(e, GaussianKernel, PluginBandwidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 1.06 * 2 * math.Pow(2000, -0.2); !floatsEqual(k.Bandwidth, expected, 0.1*expected) {
+		t.Fatalf("expected %v\n got %v\n", expected, k.Bandwidth)
+	}
+}
+
+func Test_NewKDE_Errors(t *testing.T) {
+	if _, err := NewKDE(&Empirical{}, GaussianKernel, SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	sk := NewSketchedEmpirical(0)
+	sk.Add(1, 2, 3)
+	if _, err := NewKDE(sk, GaussianKernel, SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+
+	e := &Empirical{}
+	e.Add(2, 2, 2)
+	if _, err := NewKDE(e, GaussianKernel, SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on zero variance")
+	}
+
+	e.Add(0.5)
+	if _, err := NewKDE(e, Kernel(9), SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on invalid kernel")
+	}
+	if _, err := NewKDE(e, GaussianKernel, BandwidthRule(9)); err == nil {
+		t.Fatal("expected error on invalid bandwidth rule")
+	}
+	if _, err := NewUnitKDE(e, GaussianKernel, SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on values outside of the unit interval")
+	}
+
+	if _, err := (&KDE{}).Mean(); err == nil {
+		t.Fatal("expected error on invalid KDE")
+	}
+}
+
+func Test_KDE(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	n := Normal{Mu: 3, Sigma: 1, Rand: rnd}
+	for i := 0; i < 500; i++ {
+		v, _ := n.Float64()
+		e.Add(v)
+	}
+	sampleMean, _ := e.Mean()
+	sampleVar, _ := e.Variance()
+
+	for _, kn := range kernels {
+		k, err := NewKDE(e, kn, SilvermanBandwidth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mean, _ := k.Mean()
+		variance, _ := k.Variance()
+		if !floatsPicoEqual(mean, sampleMean) || !floatsPicoEqual(variance, sampleVar+k.Bandwidth*k.Bandwidth) {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", sampleMean, sampleVar+k.Bandwidth*k.Bandwidth, mean, variance)
+		}
+
+		// the density is the derivative of the CDF.
+		for _, x := range []float64{1.5, 2.9, 4} {
+			expected, _ := k.PDF(x)
+			lo, _ := k.CDF(x - 1e-6)
+			hi, _ := k.CDF(x + 1e-6)
+			if actual := (hi - lo) / 2e-6; !floatsEqual(actual, expected, 1e-6) {
+				t.Fatalf("expected %v\n got %v for kernel %v\n", expected, actual, kn)
+			}
+		}
+
+		for _, p := range []float64{0.01, 0.5, 0.9} {
+			x, err := k.Quantile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual, _ := k.CDF(x); !floatsNanoEqual(actual, p) {
+				t.Fatalf("expected %v\n got %v for kernel %v\n", p, actual, kn)
+			}
+		}
+
+		median, _ := k.Median()
+		if sampleMedian, _ := e.Median(); !floatsDeciEqual(median, sampleMedian) {
+			t.Fatalf("expected %v\n got %v for kernel %v\n", sampleMedian, median, kn)
+		}
+
+		// the mode is the highest point of the density.
+		mode, _ := k.Mode()
+		fm, _ := k.PDF(mode)
+		for x := 0.0; x < 6; x += 0.01 {
+			if f, _ := k.PDF(x); f > fm {
+				t.Fatalf("expected mode\n got %v with density %v below %v at %v for kernel %v\n", mode, fm, f, x, kn)
+			}
+		}
+
+		// variates are drawn from the smoothed density.
+		k.Rand = rnd
+		samples := &Empirical{}
+		for i := 0; i < 20000; i++ {
+			v, _ := k.Float64()
+			samples.Add(v)
+		}
+		m, _ := samples.Mean()
+		v, _ := samples.Variance()
+		if !floatsEqual(m, mean, 0.03) || !floatsEqual(v, variance, 0.05) {
+			t.Fatalf("expected %v, %v\n got %v, %v for kernel %v\n", mean, variance, m, v, kn)
+		}
+	}
+
+	if _, err := (&KDE{sample: []float64{1}, Bandwidth: 1}).Quantile(2); err == nil {
+		t.Fatal("expected error on invalid probability")
+	}
+}
+
+func Test_UnitKDE(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	for i := 0; i < 1000; i++ {
+		e.Add(rnd.Float64())
+	}
+
+	for _, kn := range kernels {
+		k, err := NewUnitKDE(e, kn, SilvermanBandwidth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the reflected density is not biased downwards at the
+		// boundaries of a uniform sample.
+		for _, x := range []float64{0, 1} {
+			if f, _ := k.PDF(x); !floatsEqual(f, 1, 0.15) {
+				t.Fatalf("expected %v\n got %v at %v for kernel %v\n", 1, f, x, kn)
+			}
+		}
+		if f, _ := k.PDF(-0.01); f != 0 {
+			t.Fatalf("expected %v\n got %v for kernel %v\n", 0, f, kn)
+		}
+
+		// no mass is lost at the boundaries.
+		if mass, _ := k.CDF(math.Nextafter(1, 0)); !floatsNanoEqual(mass, 1) {
+			t.Fatalf("expected %v\n got %v for kernel %v\n", 1, mass, kn)
+		}
+		for _, x := range []float64{0.01, 0.2, 0.7} {
+			expected, _ := k.PDF(x)
+			lo, _ := k.CDF(x - 1e-6)
+			hi, _ := k.CDF(x + 1e-6)
+			if actual := (hi - lo) / 2e-6; !floatsEqual(actual, expected, 1e-6) {
+				t.Fatalf("expected %v\n got %v for kernel %v\n", expected, actual, kn)
+			}
+		}
+
+		mean, _ := k.Mean()
+		variance, _ := k.Variance()
+		if !floatsCentiEqual(mean, 0.5) || !floatsCentiEqual(variance, 1.0/12) {
+			t.Fatalf("expected %v, %v\n got %v, %v for kernel %v\n", 0.5, 1.0/12, mean, variance, kn)
+		}
+
+		k.Rand = rnd
+		for i := 0; i < 1000; i++ {
+			if v, _ := k.Float64(); v < 0 || v > 1 {
+				t.Fatalf("expected value in [0, 1]\n got %v for kernel %v\n", v, kn)
+			}
+		}
+	}
+}
+
+// for a bandwidth wide enough that the reflected kernels extend beyond
+// the unit interval, the density is normalised in the same way as
+// variates are drawn.
+func Test_UnitKDE_Wide(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	e := &Empirical{}
+	for i := 0; i < 50; i++ {
+		e.Add(rnd.Float64() * rnd.Float64())
+	}
+
+	for _, kn := range kernels {
+		k, err := NewUnitKDE(e, kn, SilvermanBandwidth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.Bandwidth, k.Rand = 1.5, rnd
+
+		if mass := k.integrate(func(x, f float64) float64 { return f }); !floatsNanoEqual(mass, 1) {
+			t.Fatalf("expected %v\n got %v for kernel %v\n", 1, mass, kn)
+		}
+
+		draws := &Empirical{}
+		for i := 0; i < 20000; i++ {
+			v, _ := k.Float64()
+			draws.Add(v)
+		}
+		res, err := KolmogorovSmirnov(draws, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.PValue < 1e-3 {
+			t.Fatalf("expected p-value above %v\n got %v for kernel %v\n", 1e-3, res.PValue, kn)
+		}
+	}
+}
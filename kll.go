@@ -0,0 +1,190 @@
+package godist
+
+import (
+	"math"
+	"sort"
+)
+
+// kllSketch is a KLL quantile sketch, as described by Karnin, Lang and
+// Liberty in "Optimal Quantile Approximation in Streams" (2016).
+//
+// Values are added to the bottom level of a hierarchy of compactors.
+// When the sketch is full, a level is compacted by sorting it and
+// promoting every other value, chosen with a random offset, to the level
+// above, where each value represents twice as many original values.
+// Every level above the bottom is kept sorted. Capacities decrease
+// geometrically towards the lower levels, so the sketch retains O(k)
+// values regardless of the number added.
+type kllSketch struct {
+	k      int
+	levels [][]float64
+	size   int // number of retained values
+	n      int // number of values added
+}
+
+// newKLLSketch returns an empty sketch with accuracy parameter k.
+func newKLLSketch(k int) *kllSketch {
+	return &kllSketch{k: k, levels: make([][]float64, 1)}
+}
+
+// capacity returns the maximum number of values held at level h.
+func (s *kllSketch) capacity(h int) int {
+	depth := len(s.levels) - h - 1
+	return int(math.Ceil(float64(s.k)*math.Pow(2.0/3.0, float64(depth)))) + 1
+}
+
+// maxSize returns the number of values that may be retained before the
+// sketch must be compressed.
+func (s *kllSketch) maxSize() int {
+	var size int
+	for h := range s.levels {
+		size += s.capacity(h)
+	}
+	return size
+}
+
+// add adds v to the sketch.
+func (s *kllSketch) add(rnd Rand, v float64) {
+	s.levels[0] = append(s.levels[0], v)
+	s.size++
+	s.n++
+	if s.size >= s.maxSize() {
+		s.compress(rnd)
+	}
+}
+
+// merge adds all the values summarised by other to the sketch.
+func (s *kllSketch) merge(rnd Rand, other *kllSketch) {
+	for len(s.levels) < len(other.levels) {
+		s.levels = append(s.levels, nil)
+	}
+	for h, lvl := range other.levels {
+		if h == 0 {
+			s.levels[h] = append(s.levels[h], lvl...)
+		} else {
+			s.levels[h] = mergeSorted(s.levels[h], lvl)
+		}
+	}
+	s.size += other.size
+	s.n += other.n
+
+	for s.size >= s.maxSize() {
+		s.compress(rnd)
+	}
+}
+
+// compress compacts the lowest level which is at capacity.
+func (s *kllSketch) compress(rnd Rand) {
+	for h := 0; h < len(s.levels); h++ {
+		if len(s.levels[h]) < s.capacity(h) {
+			continue
+		}
+		if h+1 == len(s.levels) {
+			s.levels = append(s.levels, nil)
+		}
+
+		lvl := s.levels[h]
+		sort.Float64s(lvl)
+
+		// an odd value out remains at this level.
+		var keep []float64
+		if len(lvl)%2 == 1 {
+			keep, lvl = []float64{lvl[len(lvl)-1]}, lvl[:len(lvl)-1]
+		}
+
+		promoted := make([]float64, 0, len(lvl)/2)
+		for i := randIntn(rnd, 2); i < len(lvl); i += 2 {
+			promoted = append(promoted, lvl[i])
+		}
+		s.levels[h+1] = mergeSorted(s.levels[h+1], promoted)
+		s.size -= len(lvl) / 2
+		s.levels[h] = append(lvl[:0], keep...)
+		return
+	}
+}
+
+// mergeSorted returns the values of a and b in sorted order, where a
+// and b are each sorted.
+func mergeSorted(a, b []float64) []float64 {
+	out := make([]float64, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if b[0] < a[0] {
+			out, b = append(out, b[0]), b[1:]
+		} else {
+			out, a = append(out, a[0]), a[1:]
+		}
+	}
+	out = append(out, a...)
+	return append(out, b...)
+}
+
+// weighted returns every retained value with its weight, sorted by
+// value.
+func (s *kllSketch) weighted() ([]float64, []float64) {
+	vals, weights := make([]float64, 0, s.size), make([]float64, 0, s.size)
+	for h, lvl := range s.levels {
+		w := math.Ldexp(1, h)
+		for _, v := range lvl {
+			vals = append(vals, v)
+			weights = append(weights, w)
+		}
+	}
+
+	sort.Sort(byValue{vals: vals, weights: weights})
+	return vals, weights
+}
+
+// quantile returns the approximate p-quantile of the values added to
+// the sketch, i.e., the smallest retained value whose approximate rank
+// is at least p.
+func (s *kllSketch) quantile(p float64) float64 {
+	vals, weights := s.weighted()
+	target := p * float64(s.n)
+
+	var cum float64
+	for i, w := range weights {
+		cum += w
+		if cum >= target {
+			return vals[i]
+		}
+	}
+	return vals[len(vals)-1]
+}
+
+// sample returns one of the retained values at random, chosen with
+// probability proportional to its weight.
+func (s *kllSketch) sample(rnd Rand) float64 {
+	target := randFloat64(rnd) * float64(s.n)
+
+	var cum float64
+	for h, lvl := range s.levels {
+		w := math.Ldexp(1, h)
+		for _, v := range lvl {
+			cum += w
+			if cum > target {
+				return v
+			}
+		}
+	}
+
+	// only reachable through rounding error.
+	for h := len(s.levels) - 1; h >= 0; h-- {
+		if n := len(s.levels[h]); n > 0 {
+			return s.levels[h][n-1]
+		}
+	}
+	return math.NaN()
+}
+
+// byValue sorts values, and their associated weights, in increasing
+// order of value.
+type byValue struct {
+	vals, weights []float64
+}
+
+func (b byValue) Len() int           { return len(b.vals) }
+func (b byValue) Less(i, j int) bool { return b.vals[i] < b.vals[j] }
+func (b byValue) Swap(i, j int) {
+	b.vals[i], b.vals[j] = b.vals[j], b.vals[i]
+	b.weights[i], b.weights[j] = b.weights[j], b.weights[i]
+}
@@ -0,0 +1,63 @@
+package godist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_kllSketch_Size(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := newKLLSketch(50)
+	for i := 0; i < 100000; i++ {
+		s.add(rnd, rnd.Float64())
+	}
+
+	if s.n != 100000 {
+		t.Fatalf("expected %v\n got %v\n", 100000, s.n)
+	}
+
+	// the retained values must be bounded, and their total weight must
+	// equal the number of values added.
+	vals, weights := s.weighted()
+	if len(vals) != s.size || s.size > 3*50+2*len(s.levels) {
+		t.Fatalf("expected at most %v\n got %v\n", 3*50+2*len(s.levels), len(vals))
+	}
+
+	var total float64
+	for i, w := range weights {
+		total += w
+		if i > 0 && vals[i] < vals[i-1] {
+			t.Fatalf("expected sorted values\n got %v before %v\n", vals[i-1], vals[i])
+		}
+	}
+	if total != 100000 {
+		t.Fatalf("expected %v\n got %v\n", 100000, total)
+	}
+}
+
+func Test_kllSketch_Merge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a, b := newKLLSketch(50), newKLLSketch(50)
+	for i := 0; i < 50000; i++ {
+		a.add(rnd, rnd.Float64())
+		b.add(rnd, 1+rnd.Float64())
+	}
+	a.merge(rnd, b)
+
+	if a.n != 100000 || a.size >= a.maxSize() {
+		t.Fatalf("expected %v values in fewer than %v\n got %v in %v\n", 100000, a.maxSize(), a.n, a.size)
+	}
+
+	// every level above the bottom remains sorted.
+	for h, lvl := range a.levels[1:] {
+		if !sort.Float64sAreSorted(lvl) {
+			t.Fatalf("expected sorted level %v\n got %v\n", h+1, lvl)
+		}
+	}
+
+	// half of the values are below one.
+	if actual := a.quantile(0.5); !floatsDeciEqual(actual, 1) {
+		t.Fatalf("expected %v\n got %v\n", 1, actual)
+	}
+}
@@ -0,0 +1,95 @@
+package godist
+
+import "math"
+
+// moments holds the running central moments of a sample, which can be
+// updated one value at a time, or by combining the moments of two
+// samples.
+//
+// m2, m3 and m4 are the sums of the second, third and fourth powers of
+// the differences from the mean, which can be used to calculate the
+// variance, skewness and kurtosis of the sample.
+type moments struct {
+	n    float64
+	mean float64
+	m2   float64
+	m3   float64
+	m4   float64
+}
+
+// add updates the moments with the value x, using the single-pass
+// algorithm described by Terriberry in "Computing Higher-Order Moments
+// Online" (2007).
+func (m *moments) add(x float64) {
+	n1 := m.n
+	m.n++
+	delta := x - m.mean
+	deltaN := delta / m.n
+	deltaN2 := deltaN * deltaN
+	term1 := delta * deltaN * n1
+
+	m.mean += deltaN
+	m.m4 += term1*deltaN2*(m.n*m.n-3*m.n+3) + 6*deltaN2*m.m2 - 4*deltaN*m.m3
+	m.m3 += term1*deltaN*(m.n-2) - 3*deltaN*m.m2
+	m.m2 += term1
+}
+
+// merge updates the moments with those of another sample, using the
+// pairwise formulae described by Chan et al. in "Updating Formulae and a
+// Pairwise Algorithm for Computing Sample Variances" (1979), and extended
+// to higher moments by Pébay in "Formulas for Robust, One-Pass Parallel
+// Computation of Covariances and Arbitrary-Order Statistical Moments"
+// (2008).
+func (m *moments) merge(o moments) {
+	if o.n == 0 {
+		return
+	} else if m.n == 0 {
+		*m = o
+		return
+	}
+
+	na, nb := m.n, o.n
+	n := na + nb
+	delta := o.mean - m.mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+
+	mean := m.mean + nb*deltaN
+	m2 := m.m2 + o.m2 + delta*deltaN*na*nb
+	m3 := m.m3 + o.m3 + delta*deltaN2*na*nb*(na-nb) +
+		3*deltaN*(na*o.m2-nb*m.m2)
+	m4 := m.m4 + o.m4 + delta*deltaN2*deltaN*na*nb*(na*na-na*nb+nb*nb) +
+		6*deltaN2*(na*na*o.m2+nb*nb*m.m2) + 4*deltaN*(na*o.m3-nb*m.m3)
+
+	*m = moments{n: n, mean: mean, m2: m2, m3: m3, m4: m4}
+}
+
+// remove updates the moments to exclude those of a sample which was
+// previously added or merged, by inverting the pairwise formulae used by
+// merge. Removing every value resets the moments.
+//
+// Downdating is less numerically stable than updating, so the moments
+// may accumulate rounding errors where most of a sample is removed.
+func (m *moments) remove(o moments) {
+	if o.n == 0 {
+		return
+	} else if o.n >= m.n {
+		*m = moments{}
+		return
+	}
+
+	n, nb := m.n, o.n
+	na := n - nb
+	mean := (n*m.mean - nb*o.mean) / na
+	delta := o.mean - mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+
+	m2 := m.m2 - o.m2 - delta*deltaN*na*nb
+	m3 := m.m3 - o.m3 - delta*deltaN2*na*nb*(na-nb) -
+		3*deltaN*(na*o.m2-nb*m2)
+	m4 := m.m4 - o.m4 - delta*deltaN2*deltaN*na*nb*(na*na-na*nb+nb*nb) -
+		6*deltaN2*(na*na*o.m2+nb*nb*m2) - 4*deltaN*(na*o.m3-nb*m3)
+
+	*m = moments{n: na, mean: mean, m2: math.Max(0, m2), m3: m3, m4: math.Max(0, m4)}
+}
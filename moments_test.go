@@ -0,0 +1,68 @@
+package godist
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_moments_merge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var a, b, all moments
+	for i := 0; i < 1000; i++ {
+		v, w := rnd.ExpFloat64(), 3+rnd.NormFloat64()
+		a.add(v)
+		all.add(v)
+		if i%3 == 0 {
+			b.add(w)
+			all.add(w)
+		}
+	}
+	a.merge(b)
+
+	actual := []float64{a.n, a.mean, a.m2, a.m3, a.m4}
+	expected := []float64{all.n, all.mean, all.m2, all.m3, all.m4}
+	for i := range actual {
+		if !floatsNanoEqual(actual[i], expected[i]) {
+			t.Fatalf("expected %v\n got %v\n", expected, actual)
+		}
+	}
+
+	// merging with an empty set of moments leaves them unchanged.
+	var empty moments
+	empty.merge(all)
+	all.merge(moments{})
+	if empty != all {
+		t.Fatalf("expected %v\n got %v\n", all, empty)
+	}
+}
+
+func Test_moments_remove(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var a, b, all moments
+	for i := 0; i < 1000; i++ {
+		v := rnd.ExpFloat64()
+		all.add(v)
+		if i%4 == 0 {
+			b.add(v)
+		} else {
+			a.add(v)
+		}
+	}
+	all.remove(b)
+
+	actual := []float64{all.n, all.mean, all.m2, all.m3, all.m4}
+	expected := []float64{a.n, a.mean, a.m2, a.m3, a.m4}
+	for i := range actual {
+		if !floatsNanoEqual(actual[i], expected[i]) {
+			t.Fatalf("expected %v\n got %v\n", expected, actual)
+		}
+	}
+
+	// removing every value resets the moments.
+	a.remove(a)
+	if a != (moments{}) {
+		t.Fatalf("expected %v\n got %v\n", moments{}, a)
+	}
+}
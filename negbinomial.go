@@ -0,0 +1,181 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// A NegativeBinomial distribution is a discrete probability distribution
+// of the number of failures before R > 0 successes occur, in a sequence
+// of independent trials which each succeed with probability P ∈ (0, 1].
+//
+// R need not be an integer, in which case the distribution is also known
+// as the Pólya distribution.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Int and Float64. Otherwise the default source in math/rand is used.
+type NegativeBinomial struct {
+	R    float64
+	P    float64
+	Rand Rand
+}
+
+// Mean returns the mean of the NegativeBinomial distribution, i.e.,
+// R(1 - P) / P.
+func (nb NegativeBinomial) Mean() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+	return nb.R * (1 - nb.P) / nb.P, nil
+}
+
+// Median returns the median of the NegativeBinomial distribution, i.e.,
+// the smallest k such that CDF(k) ≥ 0.5.
+func (nb NegativeBinomial) Median() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	guess, ok := quantileGuess(nb.R * (1 - nb.P) / nb.P)
+	if !ok {
+		msg := fmt.Sprintf("Median not supported for NegativeBinomial Distribution [r = %v, p = %v]", nb.R, nb.P)
+		return 0, UnsupportedError{S: msg}
+	}
+
+	cdf := func(k int) float64 { return nb.cdf(k) }
+	return float64(discreteQuantile(cdf, 0.5, guess)), nil
+}
+
+// Mode returns the mode of the NegativeBinomial distribution, i.e.,
+// ⌊(R - 1)(1 - P) / P⌋ where R > 1, and zero otherwise.
+//
+// Where (R - 1)(1 - P) / P is a positive integer the distribution is
+// bi-modal, in which case the smallest mode is returned.
+func (nb NegativeBinomial) Mode() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	if nb.R <= 1 {
+		return 0, nil
+	}
+	return math.Max(0, math.Ceil((nb.R-1)*(1-nb.P)/nb.P)-1), nil
+}
+
+// Variance returns the variance of the NegativeBinomial distribution,
+// i.e., R(1 - P) / P².
+func (nb NegativeBinomial) Variance() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+	return nb.R * (1 - nb.P) / (nb.P * nb.P), nil
+}
+
+// Skewness returns the skewness of the NegativeBinomial distribution,
+// i.e., (2 - P) / √(R(1 - P)).
+//
+// The skewness is not supported where P = 1, since the distribution is
+// then degenerate.
+func (nb NegativeBinomial) Skewness() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	if nb.P == 1 {
+		msg := fmt.Sprintf("Skewness not supported for NegativeBinomial Distribution [r = %v, p = %v]", nb.R, nb.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return (2 - nb.P) / math.Sqrt(nb.R*(1-nb.P)), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the NegativeBinomial
+// distribution, i.e., 6 / R + P² / (R(1 - P)).
+//
+// The kurtosis is not supported where P = 1, since the distribution is
+// then degenerate.
+func (nb NegativeBinomial) ExcessKurtosis() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	if nb.P == 1 {
+		msg := fmt.Sprintf("ExcessKurtosis not supported for NegativeBinomial Distribution [r = %v, p = %v]", nb.R, nb.P)
+		return 0, UnsupportedError{S: msg}
+	}
+	return 6/nb.R + nb.P*nb.P/(nb.R*(1-nb.P)), nil
+}
+
+// PMF returns the value of the probability mass function of the
+// NegativeBinomial distribution at k.
+func (nb NegativeBinomial) PMF(k int) (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	if k < 0 {
+		return 0, nil
+	} else if nb.P == 1 {
+		if k == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	// ln C(k + r - 1, k) = -ln B(k + 1, r) - ln(k + r)
+	lc := -special.LogBeta(float64(k)+1, nb.R) - math.Log(float64(k)+nb.R)
+	return math.Exp(lc + nb.R*math.Log(nb.P) + float64(k)*math.Log1p(-nb.P)), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// NegativeBinomial distribution at k, i.e., the regularized incomplete
+// beta function I_P(R, k + 1).
+func (nb NegativeBinomial) CDF(k int) (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+	return nb.cdf(k), nil
+}
+
+func (nb NegativeBinomial) cdf(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+	return special.RegIncBeta(nb.R, float64(k)+1, nb.P)
+}
+
+// Int returns a random variate from the NegativeBinomial distribution.
+//
+// Variates too large to be represented as an int are returned as
+// math.MaxInt.
+func (nb NegativeBinomial) Int() (int, error) {
+	k, err := nb.Float64()
+	return clampInt(k), err
+}
+
+// Float64 returns a random variate from the NegativeBinomial
+// distribution. The returned value is always an integer.
+//
+// Float64 generates variates as a Gamma-Poisson mixture, i.e., by
+// drawing a Poisson variate whose mean is itself drawn from a Gamma
+// distribution with shape R and rate P / (1 - P).
+func (nb NegativeBinomial) Float64() (float64, error) {
+	if ok, err := nb.valid(); !ok {
+		return 0, err
+	}
+
+	if nb.P == 1 {
+		return 0, nil
+	}
+	lam := genGammaMarsagliaTsang(nb.Rand, nb.R) * (1 - nb.P) / nb.P
+	return genPoisson(nb.Rand, lam), nil
+}
+
+func (nb NegativeBinomial) valid() (bool, error) {
+	if !(nb.R > 0) || math.IsInf(nb.R, 1) || !(nb.P > 0 && nb.P <= 1) {
+		msg := fmt.Sprintf("Invalid NegativeBinomial Distribution: [r = %v, p = %v]", nb.R, nb.P)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
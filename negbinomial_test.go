@@ -0,0 +1,135 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_NegativeBinomial_Imp_Distribution(t *testing.T) {
+	var _ Distribution = NegativeBinomial{}
+	var _ Moments = NegativeBinomial{}
+}
+
+func Test_NegativeBinomial_Moments(t *testing.T) {
+	type Example struct {
+		in                           NegativeBinomial
+		mean, median, mode, variance float64
+	}
+
+	examples := []Example{
+		// (R - 1)(1 - P) / P = 3, so modes are 2 and 3.
+		Example{in: NegativeBinomial{R: 3, P: 0.4}, mean: 4.5, median: 4, mode: 2, variance: 11.25},
+		Example{in: NegativeBinomial{R: 4, P: 0.4}, mean: 6, median: 5, mode: 4, variance: 15},
+		Example{in: NegativeBinomial{R: 0.5, P: 0.5}, mean: 0.5, median: 0, mode: 0, variance: 1},
+		// (R - 1)(1 - P) / P = 1, so modes are 0 and 1.
+		Example{in: NegativeBinomial{R: 2, P: 0.5}, mean: 2, median: 1, mode: 0, variance: 4},
+	}
+
+	for _, ex := range examples {
+		mean, _ := ex.in.Mean()
+		median, _ := ex.in.Median()
+		mode, _ := ex.in.Mode()
+		variance, _ := ex.in.Variance()
+
+		actual := []float64{mean, median, mode, variance}
+		expected := []float64{ex.mean, ex.median, ex.mode, ex.variance}
+		for i := range actual {
+			if !floatsPicoEqual(actual[i], expected[i]) {
+				t.Fatalf("expected %v\n got %v for %#v\n", expected, actual, ex.in)
+			}
+		}
+	}
+}
+
+func Test_NegativeBinomial_Skewness_Kurtosis(t *testing.T) {
+	// With R = 1, the NegativeBinomial is a Geometric distribution.
+	nb, g := NegativeBinomial{R: 1, P: 0.2}, Geometric{P: 0.2}
+	skew, _ := nb.Skewness()
+	kurtosis, _ := nb.ExcessKurtosis()
+	gskew, _ := g.Skewness()
+	gkurtosis, _ := g.ExcessKurtosis()
+	if !floatsPicoEqual(skew, gskew) || !floatsPicoEqual(kurtosis, gkurtosis) {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", gskew, gkurtosis, skew, kurtosis)
+	}
+
+	if _, err := (NegativeBinomial{R: 2, P: 1}).ExcessKurtosis(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_NegativeBinomial_Invalid(t *testing.T) {
+	inputs := []NegativeBinomial{
+		NegativeBinomial{R: 0, P: 0.5},
+		NegativeBinomial{R: 1, P: 0},
+	}
+
+	for _, nb := range inputs {
+		exp := fmt.Sprintf("Invalid NegativeBinomial Distribution: [r = %v, p = %v]", nb.R, nb.P)
+		fns := []func() (float64, error){nb.Mean, nb.Median, nb.Mode, nb.Variance, nb.Float64}
+		for _, fn := range fns {
+			if _, err := fn(); err == nil || err.Error() != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, err)
+			}
+		}
+	}
+}
+
+func Test_NegativeBinomial_PMF_CDF(t *testing.T) {
+	type Example struct {
+		in       NegativeBinomial
+		k        int
+		pmf, cdf float64
+	}
+
+	examples := []Example{
+		Example{in: NegativeBinomial{R: 3, P: 0.4}, k: 4, pmf: 0.124416, cdf: 0.580096},
+		Example{in: NegativeBinomial{R: 3, P: 0.4}, k: -1, pmf: 0, cdf: 0},
+		Example{in: NegativeBinomial{R: 1, P: 0.2}, k: 3, pmf: 0.1024, cdf: 0.5904},
+	}
+
+	for _, ex := range examples {
+		pmf, _ := ex.in.PMF(ex.k)
+		cdf, _ := ex.in.CDF(ex.k)
+		if !floatsPicoEqual(pmf, ex.pmf) || !floatsPicoEqual(cdf, ex.cdf) {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", ex.pmf, ex.cdf, pmf, cdf)
+		}
+	}
+
+	nb := NegativeBinomial{R: 2.5, P: 0.3}
+	if actual, _ := nb.PMF(2); !floatsPicoEqual(actual, 0.105676220938653) {
+		t.Fatalf("expected %v\n got %v\n", 0.105676220938653, actual)
+	}
+}
+
+func Test_NegativeBinomial_Int(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := []NegativeBinomial{
+		NegativeBinomial{R: 3, P: 0.4},
+		NegativeBinomial{R: 0.5, P: 0.1},
+		NegativeBinomial{R: 40, P: 0.7},
+	}
+
+	for _, nb := range inputs {
+		nb.Rand = rnd
+		checkDiscreteVariates(t, nb.Int, nb.PMF, 100000, 0.02)
+	}
+}
+
+func Test_NegativeBinomial_Int_Large(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, nb := range []NegativeBinomial{{R: 5, P: 1e-25}, {R: 5, P: 5e-324}} {
+		nb.Rand = rnd
+		for i := 0; i < 100; i++ {
+			v, err := nb.Float64()
+			if err != nil || v < 0 || v != math.Floor(v) {
+				t.Fatalf("expected non-negative integer\n got %v, %v for %v\n", v, err, nb)
+			}
+
+			if k, _ := nb.Int(); k < 0 {
+				t.Fatalf("expected non-negative variate\n got %v for %v\n", k, nb)
+			}
+		}
+	}
+}
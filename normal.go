@@ -0,0 +1,254 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Normal (or Gaussian) distribution is a continuous probability
+// distribution on the real line, parameterised by its mean μ and
+// standard deviation σ > 0.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type Normal struct {
+	Mu    float64
+	Sigma float64
+	Rand  Rand
+}
+
+// Mean returns the mean of the Normal distribution, i.e., μ.
+func (n Normal) Mean() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return n.Mu, nil
+}
+
+// Median returns the median of the Normal distribution, i.e., μ.
+func (n Normal) Median() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return n.Mu, nil
+}
+
+// Mode returns the mode of the Normal distribution, i.e., μ.
+func (n Normal) Mode() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return n.Mu, nil
+}
+
+// Variance returns the variance of the Normal distribution, i.e., σ².
+func (n Normal) Variance() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return n.Sigma * n.Sigma, nil
+}
+
+// Skewness returns the skewness of the Normal distribution, i.e., 0.
+func (n Normal) Skewness() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Normal distribution,
+// i.e., 0.
+func (n Normal) ExcessKurtosis() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// Entropy returns the differential entropy of the Normal distribution in
+// nats, i.e., ln(σ√(2πe)).
+func (n Normal) Entropy() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return 0.5*math.Log(2*math.Pi*math.E) + math.Log(n.Sigma), nil
+}
+
+// KL returns the Kullback-Leibler divergence of other from the Normal
+// distribution in nats.
+func (n Normal) KL(other Normal) (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	} else if ok, err := other.valid(); !ok {
+		return 0, err
+	}
+
+	r := n.Sigma / other.Sigma
+	z := (n.Mu - other.Mu) / other.Sigma
+	return 0.5*(r*r+z*z-1) - math.Log(r), nil
+}
+
+// PDF returns the value of the probability density function of the
+// Normal distribution at x.
+func (n Normal) PDF(x float64) (float64, error) {
+	lp, err := n.LogPDF(x)
+	if err != nil {
+		return 0, err
+	}
+	return math.Exp(lp), nil
+}
+
+// LogPDF returns the natural logarithm of the probability density
+// function of the Normal distribution at x.
+func (n Normal) LogPDF(x float64) (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	z := (x - n.Mu) / n.Sigma
+	return -0.5*z*z - math.Log(n.Sigma) - 0.5*math.Log(2*math.Pi), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Normal distribution at x.
+func (n Normal) CDF(x float64) (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return 0.5 * math.Erfc(-(x-n.Mu)/(n.Sigma*math.Sqrt2)), nil
+}
+
+// Quantile returns the p-quantile of the Normal distribution, i.e., the
+// value x such that CDF(x) = p.
+//
+// Quantile uses Wichura's AS241 algorithm, which is accurate to about 1
+// part in 10^16.
+func (n Normal) Quantile(p float64) (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return 0, UnsupportedError{S: msg}
+	}
+	return n.Mu + n.Sigma*stdNormalQuantile(p), nil
+}
+
+// Float64 returns a random variate from the Normal distribution.
+func (n Normal) Float64() (float64, error) {
+	if ok, err := n.valid(); !ok {
+		return 0, err
+	}
+	return n.Mu + n.Sigma*genStdNormal(n.Rand), nil
+}
+
+// genStdNormal generates a random variate from the standard Normal
+// distribution using Marsaglia's polar method, as described in
+// "A convenient method for generating normal variables" (1964).
+func genStdNormal(rnd Rand) float64 {
+	for {
+		u := 2*randFloat64(rnd) - 1
+		v := 2*randFloat64(rnd) - 1
+		s := u*u + v*v
+		if s > 0 && s < 1 {
+			return u * math.Sqrt(-2*math.Log(s)/s)
+		}
+	}
+}
+
+// Coefficients of the rational approximations used by AS241, in order
+// of increasing degree.
+var (
+	as241A = []float64{
+		3.3871328727963666080e0, 1.3314166789178437745e+2,
+		1.9715909503065514427e+3, 1.3731693765509461125e+4,
+		4.5921953931549871457e+4, 6.7265770927008700853e+4,
+		3.3430575583588128105e+4, 2.5090809287301226727e+3,
+	}
+	as241B = []float64{
+		1, 4.2313330701600911252e+1,
+		6.8718700749205790830e+2, 5.3941960214247511077e+3,
+		2.1213794301586595867e+4, 3.9307895800092710610e+4,
+		2.8729085735721942674e+4, 5.2264952788528545610e+3,
+	}
+	as241C = []float64{
+		1.42343711074968357734e0, 4.63033784615654529590e0,
+		5.76949722146069140550e0, 3.64784832476320460504e0,
+		1.27045825245236838258e0, 2.41780725177450611770e-1,
+		2.27238449892691845833e-2, 7.74545014278341407640e-4,
+	}
+	as241D = []float64{
+		1, 2.05319162663775882187e0,
+		1.67638483018380384940e0, 6.89767334985100004550e-1,
+		1.48103976427480074590e-1, 1.51986665636164571966e-2,
+		5.47593808499534494600e-4, 1.05075007164441684324e-9,
+	}
+	as241E = []float64{
+		6.65790464350110377720e0, 5.46378491116411436990e0,
+		1.78482653991729133580e0, 2.96560571828504891230e-1,
+		2.65321895265761230930e-2, 1.24266094738807843860e-3,
+		2.71155556874348757815e-5, 2.01033439929228813265e-7,
+	}
+	as241F = []float64{
+		1, 5.99832206555887937690e-1,
+		1.36929880922735805310e-1, 1.48753612908506148525e-2,
+		7.86869131145613259100e-4, 1.84631831751005468180e-5,
+		1.42151175831644588870e-7, 2.04426310338993978564e-15,
+	}
+)
+
+// stdNormalQuantile returns the p-quantile of the standard Normal
+// distribution, according to Wichura's algorithm AS241, "The Percentage
+// Points of the Normal Distribution" (1988).
+func stdNormalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	} else if p >= 1 {
+		return math.Inf(1)
+	}
+
+	q := p - 0.5
+	if math.Abs(q) <= 0.425 {
+		r := 0.180625 - q*q
+		return q * poly(as241A, r) / poly(as241B, r)
+	}
+
+	r := p
+	if q > 0 {
+		r = 1 - p
+	}
+	r = math.Sqrt(-math.Log(r))
+
+	var x float64
+	if r <= 5 {
+		r -= 1.6
+		x = poly(as241C, r) / poly(as241D, r)
+	} else {
+		r -= 5
+		x = poly(as241E, r) / poly(as241F, r)
+	}
+
+	if q < 0 {
+		return -x
+	}
+	return x
+}
+
+// poly evaluates the polynomial with coefficients c, in order of
+// increasing degree, at x using Horner's method.
+func poly(c []float64, x float64) float64 {
+	var v float64
+	for i := len(c) - 1; i >= 0; i-- {
+		v = v*x + c[i]
+	}
+	return v
+}
+
+func (n Normal) valid() (bool, error) {
+	if !(n.Sigma > 0) {
+		msg := fmt.Sprintf("Invalid Normal Distribution: [μ = %v, σ = %v]", n.Mu, n.Sigma)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
@@ -0,0 +1,184 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type normalExample struct {
+	in  Normal
+	x   float64
+	err error
+	out float64
+}
+
+func Test_Normal_Imp_Distribution(t *testing.T) {
+	var _ Distribution = Normal{}
+	var _ Moments = Normal{}
+}
+
+func Test_Normal_Moments(t *testing.T) {
+	n := Normal{Mu: -2.5, Sigma: 3}
+	mean, _ := n.Mean()
+	median, _ := n.Median()
+	mode, _ := n.Mode()
+	variance, _ := n.Variance()
+
+	if mean != -2.5 || median != -2.5 || mode != -2.5 {
+		t.Fatalf("expected %v\n got %v, %v, %v\n", -2.5, mean, median, mode)
+	}
+
+	if variance != 9 {
+		t.Fatalf("expected %v\n got %v\n", 9, variance)
+	}
+}
+
+func Test_Normal_Skewness_Kurtosis(t *testing.T) {
+	n := Normal{Mu: 3, Sigma: 2}
+	skew, _ := n.Skewness()
+	kurtosis, _ := n.ExcessKurtosis()
+	if skew != 0 || kurtosis != 0 {
+		t.Fatalf("expected 0, 0\n got %v, %v\n", skew, kurtosis)
+	}
+
+	if _, err := (Normal{}).Skewness(); err == nil {
+		t.Fatalf("expected error\n got %v\n", err)
+	}
+}
+
+func Test_Normal_Entropy_KL(t *testing.T) {
+	h, _ := Normal{Mu: 3, Sigma: 1}.Entropy()
+	if !floatsPicoEqual(h, 1.4189385332046727) {
+		t.Fatalf("expected %v\n got %v\n", 1.4189385332046727, h)
+	}
+
+	kl, _ := Normal{Mu: 0, Sigma: 1}.KL(Normal{Mu: 1, Sigma: 2})
+	if !floatsPicoEqual(kl, 0.4431471805599453) {
+		t.Fatalf("expected %v\n got %v\n", 0.4431471805599453, kl)
+	}
+}
+
+func Test_Normal_Invalid(t *testing.T) {
+	for _, n := range []Normal{Normal{Mu: 1, Sigma: 0}, Normal{Mu: 1, Sigma: -1}} {
+		exp := fmt.Sprintf("Invalid Normal Distribution: [μ = 1, σ = %v]", n.Sigma)
+		fns := []func() (float64, error){n.Mean, n.Median, n.Mode, n.Variance, n.Float64}
+		for _, fn := range fns {
+			if _, err := fn(); err == nil || err.Error() != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, err)
+			}
+		}
+
+		if _, err := n.CDF(0); err == nil || err.Error() != exp {
+			t.Fatalf("expected %v\n got %v\n", exp, err)
+		}
+	}
+}
+
+func Test_Normal_PDF(t *testing.T) {
+	examples := []normalExample{
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0, out: 0.3989422804014327},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 1, out: 0.24197072451914337},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: -1, out: 0.24197072451914337},
+		normalExample{in: Normal{Mu: 2, Sigma: 3}, x: 5, out: 0.08065690817304779},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.PDF(ex.x)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Normal_CDF(t *testing.T) {
+	examples := []normalExample{
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0, out: 0.5},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 1.96, out: 0.9750021048517795},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: -1, out: 0.15865525393145707},
+		normalExample{in: Normal{Mu: 2, Sigma: 3}, x: -1, out: 0.15865525393145707},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: math.Inf(1), out: 1},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.CDF(ex.x)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Normal_Quantile(t *testing.T) {
+	examples := []normalExample{
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0.5, out: 0},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0.975, out: 1.959963984540054},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0.9, out: 1.2815515655446004},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0.1, out: -1.2815515655446004},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 1e-10, out: -6.361340902404056},
+		normalExample{in: Normal{Mu: 10, Sigma: 2}, x: 0.9, out: 12.563103131089201},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 0, out: math.Inf(-1)},
+		normalExample{in: Normal{Mu: 0, Sigma: 1}, x: 1, out: math.Inf(1)},
+		normalExample{
+			in:  Normal{Mu: 0, Sigma: 1},
+			x:   -0.5,
+			err: fmt.Errorf("Quantile not supported for p = -0.5"),
+		},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.Quantile(ex.x)
+		if ex.err != nil && (err == nil || err.Error() != ex.err.Error()) {
+			t.Fatalf("expected %v\n got %v\n", ex.err, err)
+		}
+
+		if actual != ex.out && !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+
+	// round trip through the CDF across all branches of AS241.
+	n := Normal{Mu: 0, Sigma: 1}
+	for _, p := range []float64{1e-300, 1e-20, 1e-5, 0.02, 0.3, 0.6, 0.99, 1 - 1e-9} {
+		x, _ := n.Quantile(p)
+		actual, _ := n.CDF(x)
+		if !floatsEqual(actual, p, 1e-13*p) {
+			t.Fatalf("expected %v\n got %v\n", p, actual)
+		}
+	}
+}
+
+func Test_Normal_Float64(t *testing.T) {
+	inputs := []Normal{
+		Normal{Mu: 0, Sigma: 1},
+		Normal{Mu: -3, Sigma: 0.5},
+		Normal{Mu: 100, Sigma: 4},
+	}
+
+	for _, n := range inputs {
+		n.Rand = rand.New(rand.NewSource(1))
+		ed := Empirical{}
+		for i := 0; i < 10001; i++ {
+			v, _ := n.Float64()
+			ed.Add(v)
+		}
+
+		actual, _ := ed.Mean()
+		if !floatsEqual(actual, n.Mu, 0.05*n.Sigma) {
+			t.Fatalf("[Mean] expected %v got %v for %#v\n", n.Mu, actual, n)
+		}
+
+		actual, _ = ed.Variance()
+		if !floatsEqual(actual, n.Sigma*n.Sigma, 0.05*n.Sigma*n.Sigma) {
+			t.Fatalf("[Variance] expected %v got %v for %#v\n", n.Sigma*n.Sigma, actual, n)
+		}
+	}
+}
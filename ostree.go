@@ -0,0 +1,453 @@
+package godist
+
+import "math"
+
+// An ostree is an order-statistic tree holding a multiset of values,
+// each with a positive weight, which supports adding and removing
+// values, and finding values by rank or by cumulative weight, in
+// O(log n) expected time for n distinct values.
+//
+// The tree is a treap, as described by Seidel and Aragon in "Randomized
+// Search Trees" (1996), with a node for each distinct value. A node counts
+// the occurrences of its value and, in a weighted tree, holds the weight
+// of each occurrence in the order they were added. Every occurrence in an
+// unweighted tree has a weight of one.
+//
+// NaN values are ordered before every other value, in the same way as
+// sort.Float64s.
+type ostree struct {
+	root     *osnode
+	weighted bool
+	seed     uint64
+}
+
+type osnode struct {
+	key     float64
+	count   int
+	weight  float64
+	weights []float64
+	prio    uint64
+
+	left, right *osnode
+
+	// the number of occurrences and total weight in the subtree rooted at
+	// the node, and the node within it with the greatest weight, or the
+	// smallest value amongst those with the greatest weight.
+	size int
+	sum  float64
+	best *osnode
+}
+
+// insert adds an occurrence of key with weight w to the tree.
+func (t *ostree) insert(key, w float64) {
+	t.root = t.insertAt(t.root, key, w, 1)
+}
+
+// insertCount adds count occurrences of key to an unweighted tree.
+func (t *ostree) insertCount(key float64, count int) {
+	t.root = t.insertAt(t.root, key, 1, count)
+}
+
+func (t *ostree) insertAt(n *osnode, key, w float64, count int) *osnode {
+	switch {
+	case n == nil:
+		n = &osnode{key: key, prio: t.priority()}
+		n.push(w, count, t.weighted)
+	case keyLess(key, n.key):
+		n.left = t.insertAt(n.left, key, w, count)
+		if n.left.prio > n.prio {
+			return rotateRight(n)
+		}
+	case keyLess(n.key, key):
+		n.right = t.insertAt(n.right, key, w, count)
+		if n.right.prio > n.prio {
+			return rotateLeft(n)
+		}
+	default:
+		n.push(w, count, t.weighted)
+	}
+	n.update()
+	return n
+}
+
+// remove removes the earliest added occurrence of key from the tree, and
+// returns its weight. If key is not in the tree then ok is false.
+func (t *ostree) remove(key float64) (w float64, ok bool) {
+	t.root, w, ok = t.removeAt(t.root, key)
+	return w, ok
+}
+
+func (t *ostree) removeAt(n *osnode, key float64) (*osnode, float64, bool) {
+	if n == nil {
+		return nil, 0, false
+	}
+
+	var w float64
+	var ok bool
+	switch {
+	case keyLess(key, n.key):
+		n.left, w, ok = t.removeAt(n.left, key)
+	case keyLess(n.key, key):
+		n.right, w, ok = t.removeAt(n.right, key)
+	default:
+		w, ok = n.pop(t.weighted), true
+		if n.count == 0 {
+			return mergeNodes(n.left, n.right), w, ok
+		}
+	}
+	n.update()
+	return n, w, ok
+}
+
+// len returns the number of occurrences in the tree.
+func (t *ostree) len() int {
+	return t.root.subtreeSize()
+}
+
+// total returns the total weight of the tree.
+func (t *ostree) total() float64 {
+	return t.root.subtreeSum()
+}
+
+// at returns the value of the occurrence with the provided rank, counting
+// from zero.
+func (t *ostree) at(rank int) float64 {
+	n, _, _ := t.find(rank)
+	return n.key
+}
+
+// mode returns the value with the greatest total weight, or the smallest
+// such value where there are several.
+func (t *ostree) mode() float64 {
+	return t.root.best.key
+}
+
+// quantile returns the p-quantile of the values in an unweighted tree,
+// using sample quantile definition typ, in the same way as hyndmanFan.
+func (t *ostree) quantile(p float64, typ int) float64 {
+	return orderQuantile(t.len(), t.at, p, typ)
+}
+
+// weightedQuantile returns the p-quantile of the values in the tree, as
+// described by (*Empirical).Quantile, where occurrences of the same value
+// are in the order they were added.
+func (t *ostree) weightedQuantile(p float64) float64 {
+	size := t.len()
+	if size == 1 {
+		return t.at(0)
+	}
+
+	lo, _, _ := t.find(0)
+	hi, _, _ := t.find(size - 1)
+	first, last := lo.firstWeight(), hi.lastWeight()
+	target := first/2 + p*(t.total()-first/2-last/2)
+
+	// the first occurrence whose midpoint is at or beyond the target,
+	// and the occurrence before it.
+	var x, pos, prevX, prevPos float64
+	n, start, before := t.findWeight(target)
+	if i, at := n.locate(target-before, t.weighted); i == n.count {
+		if start+n.count == size {
+			return n.key
+		}
+		next, _, nextBefore := t.find(start + n.count)
+		x, pos = next.key, nextBefore+next.firstWeight()/2
+		prevX, prevPos = n.key, before+n.weight-n.lastWeight()/2
+	} else {
+		if i > 0 || start == 0 {
+			return n.key
+		}
+		prev, _, prevBefore := t.find(start - 1)
+		x, pos = n.key, before+at
+		prevX, prevPos = prev.key, prevBefore+prev.weight-prev.lastWeight()/2
+	}
+
+	h := (target - prevPos) / (pos - prevPos)
+	return prevX + h*(x-prevX)
+}
+
+// sample returns the value at cumulative weight u, for u in
+// [0, total), so that a uniformly distributed u gives a value with
+// probability proportional to its weight.
+func (t *ostree) sample(u float64) float64 {
+	n, _, _ := t.findWeight(u)
+	return n.key
+}
+
+// scale multiplies the weight of every occurrence in a weighted tree by
+// f.
+func (t *ostree) scale(f float64) {
+	var walk func(n *osnode)
+	walk = func(n *osnode) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		walk(n.right)
+		for i := range n.weights {
+			n.weights[i] *= f
+		}
+		n.weight *= f
+		n.update()
+	}
+	walk(t.root)
+}
+
+// count returns the number of occurrences of key in the tree.
+func (t *ostree) count(key float64) int {
+	n := t.root
+	for n != nil {
+		switch {
+		case keyLess(key, n.key):
+			n = n.left
+		case keyLess(n.key, key):
+			n = n.right
+		default:
+			return n.count
+		}
+	}
+	return 0
+}
+
+// each calls f with every node of the tree in increasing order of value.
+func (t *ostree) each(f func(n *osnode)) {
+	var walk func(n *osnode)
+	walk = func(n *osnode) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		f(n)
+		walk(n.right)
+	}
+	walk(t.root)
+}
+
+// weigh converts an unweighted tree to a weighted tree, in which every
+// existing occurrence has a weight of one.
+func (t *ostree) weigh() {
+	if t.weighted {
+		return
+	}
+
+	t.weighted = true
+	t.each(func(n *osnode) {
+		n.weights = make([]float64, n.count)
+		for i := range n.weights {
+			n.weights[i] = 1
+		}
+	})
+}
+
+// find returns the node holding the occurrence with the provided rank,
+// along with the rank of the first occurrence in the node, and the total
+// weight of the occurrences before it.
+func (t *ostree) find(rank int) (*osnode, int, float64) {
+	var start int
+	var before float64
+	n := t.root
+	for n != nil {
+		ls := n.left.subtreeSize()
+		switch {
+		case rank < start+ls:
+			n = n.left
+		case rank < start+ls+n.count:
+			return n, start + ls, before + n.left.subtreeSum()
+		default:
+			start += ls + n.count
+			before += n.left.subtreeSum() + n.weight
+			n = n.right
+		}
+	}
+	return nil, 0, 0
+}
+
+// findWeight returns the first node at which the cumulative weight of the
+// tree reaches target, along with the rank of the first occurrence in the
+// node, and the total weight of the occurrences before it. Where rounding
+// errors place target beyond the total weight, the last node is returned.
+func (t *ostree) findWeight(target float64) (*osnode, int, float64) {
+	var start int
+	var before float64
+	n := t.root
+	for n != nil {
+		lw := n.left.subtreeSum()
+		switch {
+		case n.left != nil && before+lw >= target:
+			n = n.left
+		case before+lw+n.weight >= target:
+			return n, start + n.left.subtreeSize(), before + lw
+		case n.right == nil:
+			return n, start + n.left.subtreeSize(), before + lw
+		default:
+			start += n.left.subtreeSize() + n.count
+			before += lw + n.weight
+			n = n.right
+		}
+	}
+	return nil, 0, 0
+}
+
+// priority returns a pseudo-random priority for a new node, using the
+// SplitMix64 generator.
+func (t *ostree) priority() uint64 {
+	t.seed += 0x9e3779b97f4a7c15
+	z := t.seed
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// push adds count occurrences with weight w to the node, where count is
+// one in a weighted tree.
+func (n *osnode) push(w float64, count int, weighted bool) {
+	n.count += count
+	if weighted {
+		n.weights = append(n.weights, w)
+		n.weight += w
+	} else {
+		n.weight = float64(n.count)
+	}
+}
+
+// pop removes the earliest added occurrence from the node, and returns
+// its weight.
+func (n *osnode) pop(weighted bool) float64 {
+	n.count--
+	if !weighted {
+		n.weight = float64(n.count)
+		return 1
+	}
+
+	w := n.weights[0]
+	n.weights = n.weights[1:]
+
+	// sum the remaining weights, rather than subtracting w, so that
+	// rounding errors do not accumulate.
+	n.weight = 0
+	for _, v := range n.weights {
+		n.weight += v
+	}
+	return w
+}
+
+// locate returns the index of the first occurrence in the node whose
+// midpoint is at or beyond the cumulative weight target, measured from
+// the start of the node, along with the position of its midpoint. If
+// there is no such occurrence then the index is the count of the node.
+func (n *osnode) locate(target float64, weighted bool) (int, float64) {
+	if !weighted {
+		i := int(math.Max(0, math.Ceil(target-0.5)))
+		if i >= n.count {
+			return n.count, 0
+		}
+		return i, float64(i) + 0.5
+	}
+
+	var cum float64
+	for i, w := range n.weights {
+		if pos := cum + w/2; pos >= target {
+			return i, pos
+		}
+		cum += w
+	}
+	return n.count, 0
+}
+
+// occurrence returns the weight of the i-th occurrence in the node, in
+// the order they were added.
+func (n *osnode) occurrence(i int) float64 {
+	if n.weights == nil {
+		return 1
+	}
+	return n.weights[i]
+}
+
+func (n *osnode) firstWeight() float64 {
+	if n.weights == nil {
+		return 1
+	}
+	return n.weights[0]
+}
+
+func (n *osnode) lastWeight() float64 {
+	if n.weights == nil {
+		return 1
+	}
+	return n.weights[len(n.weights)-1]
+}
+
+// update recalculates the augmented fields of the node from its
+// children.
+func (n *osnode) update() {
+	n.size, n.sum, n.best = n.count, n.weight, n
+	if l := n.left; l != nil {
+		n.size += l.size
+		n.sum += l.sum
+		if l.best.weight >= n.best.weight {
+			n.best = l.best
+		}
+	}
+	if r := n.right; r != nil {
+		n.size += r.size
+		n.sum += r.sum
+		if r.best.weight > n.best.weight {
+			n.best = r.best
+		}
+	}
+}
+
+func (n *osnode) subtreeSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *osnode) subtreeSum() float64 {
+	if n == nil {
+		return 0
+	}
+	return n.sum
+}
+
+func rotateRight(n *osnode) *osnode {
+	l := n.left
+	n.left, l.right = l.right, n
+	n.update()
+	l.update()
+	return l
+}
+
+func rotateLeft(n *osnode) *osnode {
+	r := n.right
+	n.right, r.left = r.left, n
+	n.update()
+	r.update()
+	return r
+}
+
+// mergeNodes joins two treaps, where every value in a is less than every
+// value in b.
+func mergeNodes(a, b *osnode) *osnode {
+	if a == nil {
+		return b
+	} else if b == nil {
+		return a
+	}
+
+	if a.prio > b.prio {
+		a.right = mergeNodes(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = mergeNodes(a, b.left)
+	b.update()
+	return b
+}
+
+// keyLess orders values in the same way as sort.Float64s, with NaN values
+// before every other value.
+func keyLess(a, b float64) bool {
+	return a < b || (a != a && b == b)
+}
@@ -0,0 +1,176 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_ostree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+
+	for _, weighted := range []bool{false, true} {
+		tree := ostree{weighted: weighted}
+
+		// the values in the tree, oldest first, with plenty of ties.
+		var values, weights []float64
+		for i := 0; i < 2000; i++ {
+			v, w := float64(rnd.Intn(300))/4, 1.0
+			if weighted {
+				w = 0.1 + rnd.ExpFloat64()
+			}
+			tree.insert(v, w)
+			values, weights = append(values, v), append(weights, w)
+
+			// remove the oldest occurrence of a random value.
+			if i%3 == 2 {
+				j := rnd.Intn(len(values))
+				for k := range values {
+					if values[k] == values[j] {
+						j = k
+						break
+					}
+				}
+
+				w, ok := tree.remove(values[j])
+				if !ok || w != weights[j] {
+					t.Fatalf("expected %v, %v\n got %v, %v\n", weights[j], true, w, ok)
+				}
+				values = append(values[:j], values[j+1:]...)
+				weights = append(weights[:j], weights[j+1:]...)
+			}
+		}
+
+		if _, ok := tree.remove(-1); ok {
+			t.Fatal("expected missing value")
+		}
+
+		// a stable sort keeps ties in the order they were added, as in the
+		// tree.
+		sorted := weightedSample{x: append([]float64(nil), values...), w: append([]float64(nil), weights...)}
+		sort.Stable(sorted)
+
+		var total float64
+		counts := map[float64]float64{}
+		for i, v := range sorted.x {
+			total += sorted.w[i]
+			counts[v] += sorted.w[i]
+		}
+		if tree.len() != len(values) || !floatsNanoEqual(tree.total(), total) {
+			t.Fatalf("expected %v, %v\n got %v, %v\n", len(values), total, tree.len(), tree.total())
+		}
+
+		for i, v := range sorted.x {
+			if actual := tree.at(i); actual != v {
+				t.Fatalf("expected %v\n got %v at rank %v\n", v, actual, i)
+			}
+		}
+
+		mode := sorted.x[0]
+		for v, c := range counts {
+			if c > counts[mode] || (c == counts[mode] && v < mode) {
+				mode = v
+			}
+		}
+		if tree.mode() != mode {
+			t.Fatalf("expected %v\n got %v\n", mode, tree.mode())
+		}
+
+		for _, p := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.77, 0.99, 1} {
+			for typ := 1; typ <= 9; typ++ {
+				expected := hyndmanFan(sorted.x, p, typ)
+				if actual := tree.quantile(p, typ); actual != expected {
+					t.Fatalf("expected %v\n got %v for p = %v and type %v\n", expected, actual, p, typ)
+				}
+			}
+
+			expected := weightedQuantile(sorted.x, sorted.w, p)
+			if actual := tree.weightedQuantile(p); !floatsNanoEqual(actual, expected) {
+				t.Fatalf("expected %v\n got %v for p = %v\n", expected, actual, p)
+			}
+		}
+
+		// sampling by weight inverts the cumulative weight.
+		var cum float64
+		for i, v := range sorted.x {
+			if actual := tree.sample(cum + sorted.w[i]/2); actual != v {
+				t.Fatalf("expected %v\n got %v\n", v, actual)
+			}
+			cum += sorted.w[i]
+		}
+	}
+}
+
+func Test_ostree_scale(t *testing.T) {
+	tree := ostree{weighted: true}
+	tree.insert(1, 2)
+	tree.insert(2, 3)
+	tree.insert(1, 0.5)
+	tree.scale(0.5)
+
+	if tree.total() != 2.75 || tree.mode() != 2 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 2.75, 2, tree.total(), tree.mode())
+	}
+	if w, _ := tree.remove(1); w != 1 {
+		t.Fatalf("expected %v\n got %v\n", 1, w)
+	}
+}
+
+func Test_ostree_NaN(t *testing.T) {
+	tree := ostree{}
+	tree.insert(1, 1)
+	tree.insert(math.NaN(), 1)
+	if v := tree.at(0); !math.IsNaN(v) {
+		t.Fatalf("expected %v\n got %v\n", math.NaN(), v)
+	}
+	if _, ok := tree.remove(math.NaN()); !ok || tree.len() != 1 {
+		t.Fatal("expected NaN to be removed")
+	}
+}
+
+// weightedSample sorts values x along with their weights w.
+type weightedSample struct {
+	x, w []float64
+}
+
+func (s weightedSample) Len() int           { return len(s.x) }
+func (s weightedSample) Less(i, j int) bool { return s.x[i] < s.x[j] }
+func (s weightedSample) Swap(i, j int) {
+	s.x[i], s.x[j] = s.x[j], s.x[i]
+	s.w[i], s.w[j] = s.w[j], s.w[i]
+}
+
+// weightedQuantile returns the p-quantile of the sorted sample x with
+// positive weights w, as described by (*Empirical).Quantile, by a linear
+// scan of the sample.
+func weightedQuantile(x, w []float64, p float64) float64 {
+	n := len(x)
+	if n == 1 {
+		return x[0]
+	}
+
+	var total float64
+	for _, v := range w {
+		total += v
+	}
+
+	// the position of p within the cumulative weight, where the i-th
+	// value is at its midpoint.
+	target := w[0]/2 + p*(total-w[0]/2-w[n-1]/2)
+
+	var cum, prev float64
+	for i := range x {
+		pos := cum + w[i]/2
+		cum += w[i]
+		if pos >= target {
+			if i == 0 {
+				return x[0]
+			}
+			h := (target - prev) / (pos - prev)
+			return x[i-1] + h*(x[i]-x[i-1])
+		}
+		prev = pos
+	}
+	return x[n-1]
+}
@@ -0,0 +1,188 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/e-dard/godist/special"
+)
+
+// A Poisson distribution is a discrete probability distribution over
+// the non-negative integers, which expresses the probability of a number
+// of events occurring in a fixed interval, where events occur
+// independently at a constant mean rate λ > 0.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Int and Float64. Otherwise the default source in math/rand is used.
+type Poisson struct {
+	Lambda float64
+	Rand   Rand
+}
+
+// Mean returns the mean of the Poisson distribution, i.e., λ.
+func (p Poisson) Mean() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+	return p.Lambda, nil
+}
+
+// Median returns the median of the Poisson distribution, i.e., the
+// smallest k such that CDF(k) ≥ 0.5.
+func (p Poisson) Median() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+
+	guess, ok := quantileGuess(p.Lambda)
+	if !ok {
+		msg := fmt.Sprintf("Median not supported for Poisson Distribution [λ = %v]", p.Lambda)
+		return 0, UnsupportedError{S: msg}
+	}
+
+	cdf := func(k int) float64 { return special.RegIncGammaUpper(float64(k)+1, p.Lambda) }
+	return float64(discreteQuantile(cdf, 0.5, guess)), nil
+}
+
+// Mode returns the mode of the Poisson distribution, i.e., ⌊λ⌋.
+//
+// Where λ is an integer the distribution is bi-modal, with modes λ and
+// λ - 1, in which case the smallest mode is returned.
+func (p Poisson) Mode() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+
+	return math.Max(0, math.Ceil(p.Lambda)-1), nil
+}
+
+// Variance returns the variance of the Poisson distribution, i.e., λ.
+func (p Poisson) Variance() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+	return p.Lambda, nil
+}
+
+// Skewness returns the skewness of the Poisson distribution, i.e., 1 / √λ.
+func (p Poisson) Skewness() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+	return 1 / math.Sqrt(p.Lambda), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the Poisson
+// distribution, i.e., 1 / λ.
+func (p Poisson) ExcessKurtosis() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+	return 1 / p.Lambda, nil
+}
+
+// PMF returns the value of the probability mass function of the Poisson
+// distribution at k.
+func (p Poisson) PMF(k int) (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+
+	if k < 0 {
+		return 0, nil
+	}
+	return math.Exp(float64(k)*math.Log(p.Lambda) - p.Lambda - lfactorial(k)), nil
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// Poisson distribution at k, i.e., the regularized upper incomplete
+// gamma function Q(k + 1, λ).
+func (p Poisson) CDF(k int) (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+
+	if k < 0 {
+		return 0, nil
+	}
+	return special.RegIncGammaUpper(float64(k)+1, p.Lambda), nil
+}
+
+// Int returns a random variate from the Poisson distribution.
+//
+// Variates too large to be represented as an int are returned as
+// math.MaxInt.
+func (p Poisson) Int() (int, error) {
+	k, err := p.Float64()
+	return clampInt(k), err
+}
+
+// Float64 returns a random variate from the Poisson distribution. The
+// returned value is always an integer.
+//
+// Float64 uses Knuth's multiplication method for λ < 10, and Hörmann's
+// PTRS transformed rejection method otherwise.
+func (p Poisson) Float64() (float64, error) {
+	if ok, err := p.valid(); !ok {
+		return 0, err
+	}
+	return genPoisson(p.Rand, p.Lambda), nil
+}
+
+// genPoisson generates a random variate from a Poisson distribution with
+// mean lam ≥ 0, where an infinite mean gives an infinite variate.
+func genPoisson(rnd Rand, lam float64) float64 {
+	if lam <= 0 {
+		return 0
+	} else if math.IsInf(lam, 1) {
+		return lam
+	} else if lam >= 10 {
+		return genPoissonPTRS(rnd, lam)
+	}
+
+	// Knuth (1969)
+	l, k, prod := math.Exp(-lam), 0, randFloat64(rnd)
+	for prod > l {
+		k++
+		prod *= randFloat64(rnd)
+	}
+	return float64(k)
+}
+
+// genPoissonPTRS generates a random variate from a Poisson distribution
+// with mean lam ≥ 10, according to the PTRS algorithm described by
+// Hörmann in "The transformed rejection method for generating Poisson
+// random variables" (1993).
+func genPoissonPTRS(rnd Rand, lam float64) float64 {
+	slam, llam := math.Sqrt(lam), math.Log(lam)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invalpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+
+	for {
+		u := randFloat64(rnd) - 0.5
+		v := randFloat64(rnd)
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lam + 0.43)
+
+		if us >= 0.07 && v <= vr {
+			return k
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -lam+k*llam-special.LogGamma(k+1) {
+			return k
+		}
+	}
+}
+
+func (p Poisson) valid() (bool, error) {
+	if !(p.Lambda > 0) || math.IsInf(p.Lambda, 1) {
+		msg := fmt.Sprintf("Invalid Poisson Distribution: [λ = %v]", p.Lambda)
+		return false, InvalidDistributionError{S: msg}
+	}
+	return true, nil
+}
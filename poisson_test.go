@@ -0,0 +1,132 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type poissonExample struct {
+	in  Poisson
+	k   int
+	err error
+	out float64
+}
+
+func Test_Poisson_Imp_Distribution(t *testing.T) {
+	var _ Distribution = Poisson{}
+	var _ Moments = Poisson{}
+}
+
+func Test_Poisson_Moments(t *testing.T) {
+	type Example struct {
+		in                           Poisson
+		mean, median, mode, variance float64
+	}
+
+	examples := []Example{
+		Example{in: Poisson{Lambda: 2.5}, mean: 2.5, median: 2, mode: 2, variance: 2.5},
+		Example{in: Poisson{Lambda: 10}, mean: 10, median: 10, mode: 9, variance: 10},
+		Example{in: Poisson{Lambda: 0.5}, mean: 0.5, median: 0, mode: 0, variance: 0.5},
+	}
+
+	for _, ex := range examples {
+		mean, _ := ex.in.Mean()
+		median, _ := ex.in.Median()
+		mode, _ := ex.in.Mode()
+		variance, _ := ex.in.Variance()
+
+		actual := []float64{mean, median, mode, variance}
+		expected := []float64{ex.mean, ex.median, ex.mode, ex.variance}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Fatalf("expected %v\n got %v for %#v\n", expected, actual, ex.in)
+			}
+		}
+	}
+}
+
+func Test_Poisson_Skewness_Kurtosis(t *testing.T) {
+	p := Poisson{Lambda: 4}
+	skew, _ := p.Skewness()
+	kurtosis, _ := p.ExcessKurtosis()
+	if !floatsPicoEqual(skew, 0.5) || !floatsPicoEqual(kurtosis, 0.25) {
+		t.Fatalf("expected 0.5, 0.25\n got %v, %v\n", skew, kurtosis)
+	}
+}
+
+func Test_Poisson_Invalid(t *testing.T) {
+	for _, p := range []Poisson{Poisson{Lambda: 0}, Poisson{Lambda: -1}} {
+		exp := fmt.Sprintf("Invalid Poisson Distribution: [λ = %v]", p.Lambda)
+		fns := []func() (float64, error){p.Mean, p.Median, p.Mode, p.Variance, p.Float64}
+		for _, fn := range fns {
+			if _, err := fn(); err == nil || err.Error() != exp {
+				t.Fatalf("expected %v\n got %v\n", exp, err)
+			}
+		}
+	}
+}
+
+func Test_Poisson_PMF(t *testing.T) {
+	examples := []poissonExample{
+		poissonExample{in: Poisson{Lambda: 2.5}, k: 3, out: 0.2137630172497364},
+		poissonExample{in: Poisson{Lambda: 0.5}, k: 0, out: 0.6065306597126334},
+		poissonExample{in: Poisson{Lambda: 10}, k: 10, out: 0.12511003572113394},
+		poissonExample{in: Poisson{Lambda: 10}, k: -1, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.PMF(ex.k)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Poisson_CDF(t *testing.T) {
+	examples := []poissonExample{
+		poissonExample{in: Poisson{Lambda: 2.5}, k: 3, out: 0.7575761331330659},
+		poissonExample{in: Poisson{Lambda: 0.5}, k: 0, out: 0.6065306597126334},
+		poissonExample{in: Poisson{Lambda: 10}, k: 10, out: 0.5830397501929856},
+		poissonExample{in: Poisson{Lambda: 10}, k: -1, out: 0},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.in.CDF(ex.k)
+		if err != nil {
+			t.Fatalf("expected no error\n got %v\n", err)
+		}
+
+		if !floatsPicoEqual(actual, ex.out) {
+			t.Fatalf("expected %v\n got %v\n", ex.out, actual)
+		}
+	}
+}
+
+func Test_Poisson_Int(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// the first two use Knuth's method, and the remainder PTRS.
+	for _, lam := range []float64{0.3, 7.5, 10, 42.7, 1000} {
+		p := Poisson{Lambda: lam, Rand: rnd}
+		checkDiscreteVariates(t, p.Int, p.PMF, 100000, 0.02)
+	}
+}
+
+func Test_Poisson_Int_Large(t *testing.T) {
+	p := Poisson{Lambda: 1e20, Rand: rand.New(rand.NewSource(1))}
+	for i := 0; i < 100; i++ {
+		v, err := p.Float64()
+		if err != nil || v != math.Floor(v) || math.Abs(v-p.Lambda) > 1e-8*p.Lambda {
+			t.Fatalf("expected integer near %v\n got %v, %v\n", p.Lambda, v, err)
+		}
+
+		if k, _ := p.Int(); k != math.MaxInt {
+			t.Fatalf("expected %v\n got %v\n", math.MaxInt, k)
+		}
+	}
+}
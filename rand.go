@@ -0,0 +1,46 @@
+package godist
+
+import "math/rand"
+
+// Rand is a source of uniformly distributed pseudo-random numbers in the
+// range [0, 1), which can be attached to a distribution to control how
+// its random variates are generated.
+//
+// A *rand.Rand from either math/rand or math/rand/v2 satisfies Rand, so
+// a seeded source can be attached using, e.g.,
+// rand.New(rand.NewSource(42)). Note that neither of those types are
+// safe for concurrent use, so each goroutine should use its own Rand.
+//
+// A rand.Source does not itself satisfy Rand, but can be attached using
+// SourceRand.
+type Rand interface {
+	Float64() float64
+}
+
+// SourceRand returns a Rand which generates its values from src.
+func SourceRand(src rand.Source) Rand {
+	return rand.New(src)
+}
+
+// randFloat64 returns a random value in [0, 1) from r, or from the
+// default source in math/rand if r is nil.
+func randFloat64(r Rand) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	return r.Float64()
+}
+
+// randIntn returns a random value in [0, n) from r, or from the default
+// source in math/rand if r is nil.
+func randIntn(r Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+
+	i := int(r.Float64() * float64(n))
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
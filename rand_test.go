@@ -0,0 +1,16 @@
+package godist
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_SourceRand(t *testing.T) {
+	expected := rand.New(rand.NewSource(42))
+	actual := SourceRand(rand.NewSource(42))
+	for i := 0; i < 10; i++ {
+		if e, a := expected.Float64(), actual.Float64(); a != e {
+			t.Fatalf("expected %v\n got %v\n", e, a)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package special
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_LogBeta(t *testing.T) {
+	type Example struct {
+		a, b float64
+		out  float64
+	}
+
+	examples := []Example{
+		Example{a: 1, b: 1, out: 0},
+		Example{a: 0.5, b: 0.5, out: 1.1447298858494002},
+		Example{a: 2, b: 3, out: -2.4849066497880004},
+		Example{a: 3, b: 2, out: -2.4849066497880004},
+		Example{a: 1e-8, b: 1, out: 18.420680743952367},
+		Example{a: 0.1, b: 1e6, out: 0.8711616409377844},
+		Example{a: 30, b: 20, out: -33.968820791977386},
+		Example{a: 1000, b: 1000, out: -1388.4826016359023},
+		Example{a: 1e6, b: 1e6, out: -1386300.003362921},
+		Example{a: 10, b: 1e10, out: -217.4566818238231},
+		Example{a: 1e-3, b: 1e8, out: 6.888758204644896},
+	}
+
+	for _, ex := range examples {
+		if actual := LogBeta(ex.a, ex.b); !floatsRelEqual(actual, ex.out, 1e-13) {
+			t.Fatalf("expected %v\n got %v for a = %v, b = %v\n", ex.out, actual, ex.a, ex.b)
+		}
+	}
+
+	for _, in := range [][2]float64{{0, 1}, {1, -1}, {math.NaN(), 1}} {
+		if actual := LogBeta(in[0], in[1]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
+
+func Test_RegIncBeta(t *testing.T) {
+	type Example struct {
+		a, b, x float64
+		out     float64
+	}
+
+	examples := []Example{
+		Example{a: 1, b: 1, x: 0.3, out: 0.3},
+		Example{a: 2, b: 3, x: 0, out: 0},
+		Example{a: 2, b: 3, x: 1, out: 1},
+		Example{a: 2, b: 3, x: 0.5, out: 0.6875},
+		Example{a: 3, b: 2, x: 0.5, out: 0.3125},
+		Example{a: 0.5, b: 0.5, x: 0.25, out: 1.0 / 3.0},
+		Example{a: 1e-3, b: 1e-3, x: 0.5, out: 0.5},
+		Example{a: 0.1, b: 10, x: 1e-5, out: 0.41655822619209404},
+		Example{a: 5, b: 0.2, x: 0.999, out: 0.628890079026613},
+		Example{a: 10, b: 10, x: 0.3, out: 0.032553356881300954},
+		Example{a: 100, b: 100, x: 0.45, out: 0.07838793271222053},
+		Example{a: 1000, b: 500, x: 0.68, out: 0.8636310196077903},
+		Example{a: 1e4, b: 1e4, x: 0.49, out: 0.0023370593301101496},
+		Example{a: 3, b: 1e5, x: 2e-5, out: 0.32333441073884156},
+	}
+
+	for _, ex := range examples {
+		actual := RegIncBeta(ex.a, ex.b, ex.x)
+		if !floatsRelEqual(actual, ex.out, 1e-13) {
+			t.Fatalf("expected %v\n got %v for a = %v, b = %v, x = %v\n", ex.out, actual, ex.a, ex.b, ex.x)
+		}
+	}
+
+	for _, in := range [][3]float64{{0, 1, 0.5}, {1, -1, 0.5}, {math.Inf(1), 1, 0.5}, {1, 1, math.NaN()}} {
+		if actual := RegIncBeta(in[0], in[1], in[2]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
+
+func Test_InvRegIncBeta(t *testing.T) {
+	type Example struct {
+		a, b, p float64
+		out     float64
+	}
+
+	examples := []Example{
+		Example{a: 1, b: 1, p: 0.3, out: 0.3},
+		Example{a: 0.5, b: 0.5, p: 0.25, out: 0.14644660940672624},
+		Example{a: 0.1, b: 1, p: 0.5, out: 0.0009765625},
+		Example{a: 1, b: 0.1, p: 0.5, out: 0.9990234375},
+		Example{a: 10, b: 10, p: 0.032553356881300954, out: 0.3},
+		Example{a: 1000, b: 500, p: 0.8636310196077903, out: 0.68},
+		Example{a: 1e4, b: 1e4, p: 0.0023370593301101496, out: 0.49},
+	}
+
+	for _, ex := range examples {
+		actual := InvRegIncBeta(ex.a, ex.b, ex.p)
+		if !floatsRelEqual(actual, ex.out, 1e-12) {
+			t.Fatalf("expected %v\n got %v for a = %v, b = %v, p = %v\n", ex.out, actual, ex.a, ex.b, ex.p)
+		}
+	}
+
+	// the inverse must be accurate to within a few representable values
+	// of the true root over a range of shapes and probabilities.
+	shapes := []float64{0.05, 0.5, 1, 2.5, 10, 250, 1e5}
+	for _, a := range shapes {
+		for _, b := range shapes {
+			for _, p := range []float64{1e-10, 0.001, 0.1, 0.5, 0.9, 0.999} {
+				x := InvRegIncBeta(a, b, p)
+				lo, hi := x, x
+				for i := 0; i < 4; i++ {
+					lo, hi = math.Nextafter(lo, 0), math.Nextafter(hi, 1)
+				}
+
+				tol := 1e-12 * p
+				if RegIncBeta(a, b, lo) > p+tol || RegIncBeta(a, b, hi) < p-tol {
+					t.Fatalf("expected root of %v\n got %v for a = %v, b = %v\n", p, x, a, b)
+				}
+			}
+		}
+	}
+
+	for _, in := range [][3]float64{{0, 1, 0.5}, {1, 1, -0.1}, {1, 1, 1.1}, {1, 1, math.NaN()}} {
+		if actual := InvRegIncBeta(in[0], in[1], in[2]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package special
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_LogGamma(t *testing.T) {
+	type Example struct {
+		in, out float64
+	}
+
+	examples := []Example{
+		Example{in: 1e-10, out: 23.025850929882736},
+		Example{in: 0.1, out: 2.252712651734206},
+		Example{in: 0.5, out: 0.5723649429247001},
+		Example{in: 1, out: 0},
+		Example{in: 1.0000001, out: -5.772155829918507e-08},
+		Example{in: 1.5, out: -0.12078223763524522},
+		Example{in: 2, out: 0},
+		Example{in: 2.0000001, out: 4.227843666532498e-08},
+		Example{in: 2.5, out: 0.2846828704729192},
+		Example{in: 3.7, out: 1.428072326665388},
+		Example{in: 10, out: 12.801827480081469},
+		Example{in: 100, out: 359.1342053695754},
+		Example{in: 1e5, out: 1051287.7089736569},
+		Example{in: 1e10, out: 220258509288.81058},
+		Example{in: -0.5, out: 1.2655121234846454},
+		Example{in: -2.5, out: -0.056243716497674054},
+	}
+
+	for _, ex := range examples {
+		if actual := LogGamma(ex.in); !floatsRelEqual(actual, ex.out, 1e-14) {
+			t.Fatalf("expected %v\n got %v for x = %v\n", ex.out, actual, ex.in)
+		}
+	}
+
+	for _, in := range []float64{0, -1, -2} {
+		if actual := LogGamma(in); !math.IsInf(actual, 1) {
+			t.Fatalf("expected +Inf\n got %v\n", actual)
+		}
+	}
+
+	if actual := LogGamma(math.NaN()); !math.IsNaN(actual) {
+		t.Fatalf("expected NaN\n got %v\n", actual)
+	}
+}
+
+func Test_Digamma(t *testing.T) {
+	type Example struct {
+		in, out float64
+	}
+
+	examples := []Example{
+		Example{in: 1e-8, out: -100000000.57721564},
+		Example{in: 0.1, out: -10.423754940411076},
+		Example{in: 0.25, out: -4.2274535333762655},
+		Example{in: 0.5, out: -1.9635100260214235},
+		Example{in: 1, out: -0.5772156649015329},
+		Example{in: 1.4616321449683622, out: -9.241265521729427e-17},
+		Example{in: 1.4616321459683622, out: 9.676720177913621e-10},
+		Example{in: 1.5, out: 0.03648997397857652},
+		Example{in: 2, out: 0.42278433509846713},
+		Example{in: 3.7, out: 1.1671535393615113},
+		Example{in: 10, out: 2.251752589066721},
+		Example{in: 100, out: 4.600161852738087},
+		Example{in: 1e6, out: 13.815510057964191},
+		Example{in: 1e15, out: 34.538776394910684},
+		Example{in: -0.5, out: 0.03648997397857652},
+		Example{in: -2.5, out: 1.103156640645243},
+		Example{in: math.Inf(1), out: math.Inf(1)},
+	}
+
+	for _, ex := range examples {
+		if actual := Digamma(ex.in); !floatsRelEqual(actual, ex.out, 1e-13) {
+			t.Fatalf("expected %v\n got %v for x = %v\n", ex.out, actual, ex.in)
+		}
+	}
+
+	for _, in := range []float64{0, -1, -2, math.Inf(-1), math.NaN()} {
+		if actual := Digamma(in); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
+
+func Test_Trigamma(t *testing.T) {
+	type Example struct {
+		in, out float64
+	}
+
+	examples := []Example{
+		Example{in: 1e-8, out: 1.0000000000000002e+16},
+		Example{in: 0.1, out: 101.43329915079275},
+		Example{in: 0.25, out: 17.19732915450711},
+		Example{in: 0.5, out: 4.934802200544679},
+		Example{in: 1, out: 1.6449340668482264},
+		Example{in: 2, out: 0.6449340668482264},
+		Example{in: 3.7, out: 0.3100378576700383},
+		Example{in: 10, out: 0.10516633568168575},
+		Example{in: 100, out: 0.010050166663333571},
+		Example{in: 1e6, out: 1.0000005000001667e-06},
+		Example{in: -0.5, out: 8.934802200544679},
+		Example{in: -2.5, out: 9.539246644989124},
+		Example{in: math.Inf(1), out: 0},
+	}
+
+	for _, ex := range examples {
+		if actual := Trigamma(ex.in); !floatsRelEqual(actual, ex.out, 1e-13) {
+			t.Fatalf("expected %v\n got %v for x = %v\n", ex.out, actual, ex.in)
+		}
+	}
+
+	for _, in := range []float64{0, -3, math.Inf(-1), math.NaN()} {
+		if actual := Trigamma(in); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
+
+func Test_RegIncGamma(t *testing.T) {
+	type Example struct {
+		a, x float64
+		p, q float64
+	}
+
+	examples := []Example{
+		Example{a: 1, x: 0, p: 0, q: 1},
+		Example{a: 1, x: 2, p: 0.8646647167633873, q: 0.1353352832366127},
+		Example{a: 0.1, x: 1e-5, p: 0.3323984050405033, q: 0.6676015949594967},
+		Example{a: 0.5, x: 0.3, p: 0.5614219739190002, q: 0.4385780260809999},
+		Example{a: 0.5, x: 30, p: 0.9999999999999906, q: 9.485737571073848e-15},
+		Example{a: 3, x: 2.5, p: 0.45618688411667047, q: 0.5438131158833295},
+		Example{a: 5, x: 10, p: 0.970747311923039, q: 0.029252688076961072},
+		Example{a: 10, x: 3, p: 0.0011024881301154798, q: 0.9988975118698845},
+		Example{a: 20, x: 60, p: 0.9999999993648082, q: 6.351918340378976e-10},
+		Example{a: 100, x: 90, p: 0.15822098918643016, q: 0.8417790108135699},
+		Example{a: 1000, x: 1000, p: 0.5042052441802155, q: 0.4957947558197845},
+		Example{a: 1e4, x: 10100, p: 0.8413487504471796, q: 0.15865124955282037},
+		Example{a: 1e5, x: 99000, p: 0.0007574199211747679, q: 0.9992425800788253},
+		Example{a: 1, x: math.Inf(1), p: 1, q: 0},
+	}
+
+	for _, ex := range examples {
+		if actual := RegIncGamma(ex.a, ex.x); !floatsRelEqual(actual, ex.p, 1e-13) {
+			t.Fatalf("expected %v\n got %v for a = %v, x = %v\n", ex.p, actual, ex.a, ex.x)
+		}
+		if actual := RegIncGammaUpper(ex.a, ex.x); !floatsRelEqual(actual, ex.q, 1e-13) {
+			t.Fatalf("expected %v\n got %v for a = %v, x = %v\n", ex.q, actual, ex.a, ex.x)
+		}
+	}
+
+	// the upper function must retain precision deep into the tail.
+	if actual := RegIncGammaUpper(1, 50); !floatsRelEqual(actual, math.Exp(-50), 1e-14) {
+		t.Fatalf("expected %v\n got %v\n", math.Exp(-50), actual)
+	}
+
+	for _, in := range [][2]float64{{0, 1}, {-1, 1}, {math.Inf(1), 1}, {1, math.NaN()}} {
+		if actual := RegIncGamma(in[0], in[1]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+		if actual := RegIncGammaUpper(in[0], in[1]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
+
+func Test_InvRegIncGamma(t *testing.T) {
+	for _, a := range []float64{0.01, 0.5, 1, 3.5, 20, 1000, 1e5} {
+		for _, p := range []float64{1e-100, 1e-10, 0.001, 0.1, 0.5, 0.9, 0.999} {
+			x := InvRegIncGamma(a, p)
+			lo, hi := x, x
+			for i := 0; i < 4; i++ {
+				lo, hi = math.Nextafter(lo, 0), math.Nextafter(hi, math.Inf(1))
+			}
+
+			tol := 1e-12 * p
+			if RegIncGamma(a, lo) > p+tol || RegIncGamma(a, hi) < p-tol {
+				t.Fatalf("expected root of %v\n got %v for a = %v\n", p, x, a)
+			}
+
+			// the same root must be found from the upper tail.
+			x = InvRegIncGammaUpper(a, p)
+			lo, hi = x, x
+			for i := 0; i < 4; i++ {
+				lo, hi = math.Nextafter(lo, 0), math.Nextafter(hi, math.Inf(1))
+			}
+
+			if RegIncGammaUpper(a, lo) < p-tol || RegIncGammaUpper(a, hi) > p+tol {
+				t.Fatalf("expected upper root of %v\n got %v for a = %v\n", p, x, a)
+			}
+		}
+	}
+
+	if actual := InvRegIncGamma(2, 0); actual != 0 {
+		t.Fatalf("expected 0\n got %v\n", actual)
+	}
+	if actual := InvRegIncGamma(2, 1); !math.IsInf(actual, 1) {
+		t.Fatalf("expected +Inf\n got %v\n", actual)
+	}
+
+	for _, in := range [][2]float64{{0, 0.5}, {1, -0.1}, {1, 1.1}, {1, math.NaN()}} {
+		if actual := InvRegIncGamma(in[0], in[1]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+		if actual := InvRegIncGammaUpper(in[0], in[1]); !math.IsNaN(actual) {
+			t.Fatalf("expected NaN\n got %v\n", actual)
+		}
+	}
+}
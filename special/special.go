@@ -0,0 +1,25 @@
+// Package special provides the special functions which underpin the
+// distributions in godist, such as the log-gamma, digamma and incomplete
+// beta and gamma functions, and their inverses.
+//
+// Functions return NaN where their arguments are outside of their
+// domains, in the same way as the functions in the standard math
+// package.
+package special
+
+const (
+	// maximum number of iterations used when evaluating continued
+	// fractions and series.
+	maxIter = 10000
+
+	// relative accuracy required when evaluating continued fractions and
+	// series.
+	cfEpsilon = 1e-16
+
+	// a number near the smallest representable float64, used to avoid
+	// division by zero in Lentz's method.
+	cfTiny = 1e-300
+
+	// the difference between 1 and the next representable float64.
+	epsilon = 2.220446049250313e-16
+)
@@ -0,0 +1,13 @@
+package special
+
+import "math"
+
+// floatsRelEqual determines if two values are equal to within a relative
+// tolerance of tol. Values which are both zero, or both infinite with
+// the same sign, are considered equal.
+func floatsRelEqual(f1, f2, tol float64) bool {
+	if f1 == f2 {
+		return true
+	}
+	return math.Abs(f1-f2) <= tol*math.Max(math.Abs(f1), math.Abs(f2))
+}
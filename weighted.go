@@ -0,0 +1,125 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddWeighted adds a value to the empirical sample with the provided
+// weight, which must be non-negative and finite. Values added with Add
+// have a weight of one, and values with a weight of zero are ignored.
+//
+// The moments of the distribution are updated using the incremental
+// algorithm for weighted samples described by West in "Updating Mean and
+// Variance Estimates: An Improved Method" (1979), so that Mean and
+// Variance return the weighted mean and variance of the sample.
+//
+// AddWeighted is not supported on sketched distributions.
+func (e *Empirical) AddWeighted(value, weight float64) error {
+	if e.sketch != nil {
+		msg := "weighted values cannot be added to a sketched distribution."
+		return UnsupportedError{S: msg}
+	}
+
+	if err := validWeight(weight); err != nil {
+		return err
+	} else if weight == 0 {
+		return nil
+	}
+
+	e.tree.weigh()
+	e.tree.insert(value, weight)
+	e.changed()
+
+	// merging the moments of a single weighted value is West's update.
+	e.moments.merge(moments{n: weight, mean: value})
+	return nil
+}
+
+// validWeight returns an error if weight cannot be used as the weight of
+// a value.
+func validWeight(weight float64) error {
+	if !(weight >= 0) || math.IsInf(weight, 1) {
+		msg := fmt.Sprintf("weight %v not supported.", weight)
+		return UnsupportedError{S: msg}
+	}
+	return nil
+}
+
+// EffectiveSize returns Kish's effective sample size of the
+// distribution, (Σw)² / Σw², i.e., the number of equally weighted values
+// which would estimate the mean with the same precision as the weighted
+// sample. For a distribution without weights this is the same as Size.
+func (e *Empirical) EffectiveSize() float64 {
+	if !e.tree.weighted {
+		return e.n
+	}
+
+	var w2 float64
+	e.tree.each(func(n *osnode) {
+		for _, w := range n.weights {
+			w2 += w * w
+		}
+	})
+	return e.n * e.n / w2
+}
+
+// An aliasTable samples indices with probability proportional to a set
+// of weights in constant time, using the alias method described by Vose
+// in "A Linear Algorithm for Generating Random Numbers with a Given
+// Distribution" (1991).
+type aliasTable struct {
+	prob  []float64
+	alias []int
+}
+
+// newAliasTable returns an alias table for the positive weights w.
+func newAliasTable(w []float64) *aliasTable {
+	n := len(w)
+	var total float64
+	for _, v := range w {
+		total += v
+	}
+
+	a := &aliasTable{prob: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, v := range w {
+		scaled[i] = v * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	// pair each under-full column with an over-full one, which donates
+	// the remainder of the column.
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		a.prob[s], a.alias[s] = scaled[s], l
+		scaled[l] += scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	// any remaining columns are full, up to rounding errors.
+	for _, i := range append(small, large...) {
+		a.prob[i] = 1
+	}
+	return a
+}
+
+// sample returns a random index from the table.
+func (a *aliasTable) sample(rnd Rand) int {
+	i := randIntn(rnd, len(a.prob))
+	if randFloat64(rnd) < a.prob[i] {
+		return i
+	}
+	return a.alias[i]
+}
@@ -0,0 +1,185 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_Empirical_AddWeighted(t *testing.T) {
+	// a weight of two is the same as adding a value twice, other than for
+	// quantiles, which treat each value as a single point.
+	e, w := &Empirical{}, &Empirical{}
+	e.Add(1, 2, 2, 3, 7, 7, 7)
+	w.Add(1)
+	for _, v := range [][2]float64{{2, 2}, {3, 1}, {7, 3}} {
+		if err := w.AddWeighted(v[0], v[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := []func(*Empirical) (float64, error){
+		(*Empirical).Mean, (*Empirical).Variance, (*Empirical).Skewness,
+		(*Empirical).ExcessKurtosis, (*Empirical).Mode, (*Empirical).Entropy,
+	}
+	for i, stat := range stats {
+		expected, _ := stat(e)
+		actual, err := stat(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !floatsPicoEqual(actual, expected) {
+			t.Fatalf("[%d] expected %v\n got %v\n", i, expected, actual)
+		}
+	}
+
+	if w.Size() != 7 {
+		t.Fatalf("expected %v\n got %v\n", 7, w.Size())
+	}
+
+	// values added without a weight have a weight of one.
+	w.Add(2, 9)
+	e.Add(2, 9)
+	for i, stat := range stats {
+		expected, _ := stat(e)
+		if actual, _ := stat(w); !floatsPicoEqual(actual, expected) {
+			t.Fatalf("[%d] expected %v\n got %v\n", i, expected, actual)
+		}
+	}
+
+	// a weight of zero is ignored.
+	if err := w.AddWeighted(100, 0); err != nil {
+		t.Fatal(err)
+	}
+	if w.Size() != 9 {
+		t.Fatalf("expected %v\n got %v\n", 9, w.Size())
+	}
+
+	for _, weight := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if err := w.AddWeighted(1, weight); err == nil {
+			t.Fatalf("expected error for weight %v\n", weight)
+		}
+	}
+
+	sk := NewSketchedEmpirical(0)
+	if err := sk.AddWeighted(1, 1); err == nil {
+		t.Fatal("expected error on sketched distribution")
+	}
+
+	if _, err := w.QuantileType(0.5, 7); err == nil {
+		t.Fatal("expected error on weighted distribution")
+	}
+	if _, err := w.Frequencies(); err == nil {
+		t.Fatal("expected error on weighted distribution")
+	}
+	if _, err := NewKDE(w, GaussianKernel, SilvermanBandwidth); err == nil {
+		t.Fatal("expected error on weighted distribution")
+	}
+}
+
+func Test_Empirical_AddWeighted_Moments(t *testing.T) {
+	type Example struct {
+		Values, Weights []float64
+		Mean, Variance  float64
+	}
+
+	examples := []Example{
+		{Values: []float64{1, 2}, Weights: []float64{1, 3}, Mean: 1.75, Variance: 0.1875},
+		{Values: []float64{-2, 0, 4}, Weights: []float64{0.5, 0.25, 0.25}, Mean: 0, Variance: 6},
+		{Values: []float64{10}, Weights: []float64{0.3}, Mean: 10, Variance: 0},
+	}
+
+	for i, ex := range examples {
+		e := &Empirical{}
+		for j, v := range ex.Values {
+			e.AddWeighted(v, ex.Weights[j])
+		}
+
+		mean, _ := e.Mean()
+		variance, _ := e.Variance()
+		if !floatsPicoEqual(mean, ex.Mean) || !floatsPicoEqual(variance, ex.Variance) {
+			t.Fatalf("[%d] expected %v, %v\n got %v, %v\n", i, ex.Mean, ex.Variance, mean, variance)
+		}
+	}
+}
+
+func Test_Empirical_WeightedQuantile(t *testing.T) {
+	e := &Empirical{}
+	e.AddWeighted(3, 2)
+	e.AddWeighted(1, 1)
+	e.AddWeighted(2, 1)
+
+	// midpoints at 0.5, 1.5 and 3, rescaled over [0.5, 3].
+	type Example struct {
+		P, Q float64
+	}
+	examples := []Example{{P: 0, Q: 1}, {P: 0.4, Q: 2}, {P: 0.7, Q: 2.5}, {P: 1, Q: 3}}
+	for _, ex := range examples {
+		if q, _ := e.Quantile(ex.P); !floatsPicoEqual(q, ex.Q) {
+			t.Fatalf("expected %v\n got %v for p = %v\n", ex.Q, q, ex.P)
+		}
+	}
+
+	if m, _ := e.Median(); !floatsPicoEqual(m, 2+1.0/6) {
+		t.Fatalf("expected %v\n got %v\n", 2+1.0/6, m)
+	}
+	if m, _ := e.Mode(); m != 3 {
+		t.Fatalf("expected %v\n got %v\n", 3, m)
+	}
+}
+
+func Test_Empirical_EffectiveSize(t *testing.T) {
+	e := &Empirical{}
+	e.Add(1, 2, 3)
+	if e.EffectiveSize() != 3 {
+		t.Fatalf("expected %v\n got %v\n", 3, e.EffectiveSize())
+	}
+
+	// (1 + 1 + 1 + 3)² / (1 + 1 + 1 + 9) = 3.
+	e.AddWeighted(4, 3)
+	if !floatsPicoEqual(e.EffectiveSize(), 3) {
+		t.Fatalf("expected %v\n got %v\n", 3, e.EffectiveSize())
+	}
+}
+
+func Test_Empirical_WeightedFloat64(t *testing.T) {
+	e := &Empirical{Rand: rand.New(rand.NewSource(42))}
+	weights := []float64{1, 0.1, 2.4, 0.5}
+	for i, w := range weights {
+		e.AddWeighted(float64(i), w)
+	}
+
+	counts := map[float64]float64{}
+	for i := 0; i < 100000; i++ {
+		v, _ := e.Float64()
+		counts[v]++
+
+		// sorting the sample rebuilds the table.
+		if i == 50000 {
+			e.Median()
+		}
+	}
+
+	for i, w := range weights {
+		if p := counts[float64(i)] / 100000; !floatsCentiEqual(p, w/4) {
+			t.Fatalf("expected %v\n got %v for value %v\n", w/4, p, i)
+		}
+	}
+}
+
+func Test_newAliasTable(t *testing.T) {
+	w := []float64{1, 2, 3, 0.5, 3.5}
+	a := newAliasTable(w)
+
+	// the probability of each index summed across the columns.
+	p := make([]float64, len(w))
+	for i := range w {
+		p[i] += a.prob[i] / float64(len(w))
+		p[a.alias[i]] += (1 - a.prob[i]) / float64(len(w))
+	}
+	for i := range w {
+		if !floatsPicoEqual(p[i], w[i]/10) {
+			t.Fatalf("expected %v\n got %v for index %v\n", w[i]/10, p[i], i)
+		}
+	}
+}
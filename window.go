@@ -0,0 +1,402 @@
+package godist
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// number of half-lives after which a value is discarded from a
+	// DecayedEmpirical distribution, at which point its weight is 2^-40,
+	// or roughly 1e-12, of the weight of the newest value.
+	decayHalfLives = 40
+
+	// weight beyond which the weights in a DecayedEmpirical distribution
+	// are rescaled, to avoid overflow.
+	decayRescale = 1e100
+
+	// the range of half-lives supported by a DecayedEmpirical
+	// distribution. Below the minimum the weights grow by more than 2^64
+	// per value, and so could overflow before being rescaled, while above
+	// the maximum the decay of the weights is lost to rounding errors.
+	decayMinHalfLife = 1.0 / 64
+	decayMaxHalfLife = 1e15
+)
+
+// A WindowedEmpirical distribution is an Empirical distribution of the
+// most recently added values in a stream, up to a fixed number of
+// values. Adding a value to a full window evicts the oldest value.
+//
+// The moments of the window are updated incrementally as values enter
+// and leave it, and its values are kept in an order-statistic tree, so
+// that Add, Median, Quantile and Mode take O(log n) time for a window of
+// n values.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type WindowedEmpirical struct {
+	Rand Rand
+	window
+
+	size int
+}
+
+// NewWindowedEmpirical returns an empty WindowedEmpirical distribution
+// of the most recent size values.
+func NewWindowedEmpirical(size int) (*WindowedEmpirical, error) {
+	if size < 1 {
+		msg := fmt.Sprintf("window size %v not supported.", size)
+		return nil, UnsupportedError{S: msg}
+	}
+	return &WindowedEmpirical{size: size}, nil
+}
+
+// Add adds one or more values to the window, evicting the oldest values
+// once the window is full.
+func (e *WindowedEmpirical) Add(values ...float64) {
+	for _, v := range values {
+		e.push(v, 1, 0)
+		if e.len() > e.size {
+			e.pop()
+		}
+	}
+}
+
+// Float64 returns a randomly sampled value from the window.
+func (e *WindowedEmpirical) Float64() (float64, error) {
+	return e.float64(e.Rand)
+}
+
+// A TimeWindowedEmpirical distribution is an Empirical distribution of
+// the values in a stream which were added within a fixed duration of the
+// current time. Values are evicted once they are older than the
+// duration, either when values are added, or before the distribution is
+// read.
+//
+// The window is maintained in the same way as a WindowedEmpirical
+// distribution.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used. Now is
+// optional, and when set is used as the source of the current time.
+// Otherwise time.Now is used.
+type TimeWindowedEmpirical struct {
+	Rand Rand
+	Now  func() time.Time
+	window
+
+	d time.Duration
+}
+
+// NewTimeWindowedEmpirical returns an empty TimeWindowedEmpirical
+// distribution of the values added within the duration d.
+func NewTimeWindowedEmpirical(d time.Duration) (*TimeWindowedEmpirical, error) {
+	if d <= 0 {
+		msg := fmt.Sprintf("window duration %v not supported.", d)
+		return nil, UnsupportedError{S: msg}
+	}
+
+	e := &TimeWindowedEmpirical{d: d}
+	e.window.expire = e.expire
+	return e, nil
+}
+
+// Add adds one or more values to the window at the current time, and
+// evicts any values which have expired.
+func (e *TimeWindowedEmpirical) Add(values ...float64) {
+	now := e.now()
+	for _, v := range values {
+		e.push(v, 1, now)
+	}
+	e.expire()
+}
+
+// Float64 returns a randomly sampled value from the window.
+func (e *TimeWindowedEmpirical) Float64() (float64, error) {
+	e.expire()
+	return e.float64(e.Rand)
+}
+
+// expire evicts the values which were added at or before the start of the
+// window.
+func (e *TimeWindowedEmpirical) expire() {
+	cutoff := e.now() - int64(e.d)
+	for e.len() > 0 && e.entries[e.head].at <= cutoff {
+		e.pop()
+	}
+}
+
+func (e *TimeWindowedEmpirical) now() int64 {
+	if e.Now != nil {
+		return e.Now().UnixNano()
+	}
+	return time.Now().UnixNano()
+}
+
+// A DecayedEmpirical distribution is a weighted Empirical distribution of
+// the values in a stream, where the weight of each value decays
+// exponentially with the number of values added after it, halving every
+// half-life.
+//
+// Mean and Variance are exponentially weighted moving averages of the
+// stream, while Median, Quantile and Mode are calculated in the same way
+// as for a weighted Empirical distribution. Values are discarded after 40
+// half-lives, which has a negligible effect on the distribution, so that
+// the memory used is bounded.
+//
+// Rand is optional, and when set is used as the source of randomness by
+// Float64. Otherwise the default source in math/rand is used.
+type DecayedEmpirical struct {
+	Rand Rand
+	window
+
+	size   int
+	growth float64
+	next   float64
+}
+
+// NewDecayedEmpirical returns an empty DecayedEmpirical distribution
+// with the provided half-life, measured in values, which must be between
+// 1/64 and 1e15.
+func NewDecayedEmpirical(halfLife float64) (*DecayedEmpirical, error) {
+	if !(halfLife >= decayMinHalfLife && halfLife <= decayMaxHalfLife) {
+		msg := fmt.Sprintf("half-life %v not supported.", halfLife)
+		return nil, UnsupportedError{S: msg}
+	}
+
+	e := &DecayedEmpirical{
+		size:   int(math.Ceil(decayHalfLives * halfLife)),
+		growth: math.Exp2(1 / halfLife),
+		next:   1,
+	}
+	e.tree.weighted = true
+	return e, nil
+}
+
+// Add adds one or more values to the distribution, decaying the weights
+// of the values already added.
+//
+// Rather than decaying every existing weight, each value is added with a
+// weight which grows exponentially, and the weights are occasionally
+// rescaled.
+func (e *DecayedEmpirical) Add(values ...float64) {
+	for _, v := range values {
+		if e.next > decayRescale {
+			e.rescale(1 / e.next)
+			e.next = 1
+		}
+
+		e.push(v, e.next, 0)
+		e.next *= e.growth
+		if e.len() > e.size {
+			e.pop()
+		}
+	}
+}
+
+// Float64 returns a randomly sampled value from the distribution, with
+// probability proportional to its current weight.
+func (e *DecayedEmpirical) Float64() (float64, error) {
+	return e.float64(e.Rand)
+}
+
+// Size returns the total weight of the values in the distribution, where
+// the newest value has a weight of one.
+func (e *DecayedEmpirical) Size() float64 {
+	return e.n * e.growth / e.next
+}
+
+// window holds the values in a moving window over a stream, oldest
+// first, along with their running moments and an order-statistic tree,
+// so that statistics of the window can be maintained incrementally as
+// values enter and leave it.
+type window struct {
+	moments
+	tree ostree
+
+	entries []windowEntry
+	head    int
+
+	// the number of values evicted since the moments were last
+	// recalculated.
+	evicted int
+
+	// expire is optional, and when set is called to evict any expired
+	// values before the window is read.
+	expire func()
+}
+
+// a windowEntry is a value in a window, along with its weight and the
+// time, in nanoseconds, at which it was added.
+type windowEntry struct {
+	value  float64
+	weight float64
+	at     int64
+}
+
+// Mean returns the mean of the values in the window.
+func (w *window) Mean() (float64, error) {
+	if err := w.read("mean"); err != nil {
+		return 0, err
+	}
+	return w.mean, nil
+}
+
+// Median returns the median of the values in the window, calculated in
+// the same way as (*Empirical).Median.
+func (w *window) Median() (float64, error) {
+	return w.Quantile(0.5)
+}
+
+// Mode returns the mode of the values in the window, i.e., the value
+// with the greatest total weight. Where there are several, the smallest
+// is returned.
+func (w *window) Mode() (float64, error) {
+	if err := w.read("mode"); err != nil {
+		return 0, err
+	}
+	return w.tree.mode(), nil
+}
+
+// Variance returns the variance of the values in the window.
+func (w *window) Variance() (float64, error) {
+	if err := w.read("variance"); err != nil {
+		return 0, err
+	}
+	return w.m2 / w.n, nil
+}
+
+// Skewness returns the skewness of the values in the window.
+func (w *window) Skewness() (float64, error) {
+	if err := w.read("skewness"); err != nil {
+		return 0, err
+	} else if w.m2 == 0 {
+		msg := "skewness cannot be calculated on distribution with zero variance."
+		return 0, UnsupportedError{S: msg}
+	}
+	return math.Sqrt(w.n) * w.m3 / math.Pow(w.m2, 1.5), nil
+}
+
+// ExcessKurtosis returns the excess kurtosis of the values in the
+// window.
+func (w *window) ExcessKurtosis() (float64, error) {
+	if err := w.read("kurtosis"); err != nil {
+		return 0, err
+	} else if w.m2 == 0 {
+		msg := "kurtosis cannot be calculated on distribution with zero variance."
+		return 0, UnsupportedError{S: msg}
+	}
+	return w.n*w.m4/(w.m2*w.m2) - 3, nil
+}
+
+// Quantile returns the p-quantile of the values in the window,
+// calculated in the same way as (*Empirical).Quantile.
+func (w *window) Quantile(p float64) (float64, error) {
+	if err := w.read("quantile"); err != nil {
+		return 0, err
+	}
+
+	if !(p >= 0 && p <= 1) {
+		msg := fmt.Sprintf("Quantile not supported for p = %v", p)
+		return 0, UnsupportedError{S: msg}
+	}
+
+	if w.tree.weighted {
+		return w.tree.weightedQuantile(p), nil
+	}
+	return w.tree.quantile(p, 7), nil
+}
+
+// Size returns the number of values in the window, or for a weighted
+// window the total weight of the values.
+func (w *window) Size() float64 {
+	if w.expire != nil {
+		w.expire()
+	}
+	return w.n
+}
+
+// read evicts any expired values, and returns an error if the named
+// statistic cannot be calculated because the window is empty.
+func (w *window) read(stat string) error {
+	if w.expire != nil {
+		w.expire()
+	}
+
+	if w.len() == 0 {
+		msg := fmt.Sprintf("%v cannot be calculated on empty distribution.", stat)
+		return InvalidDistributionError{S: msg}
+	}
+	return nil
+}
+
+// float64 returns a value from the window at random, with probability
+// proportional to its weight.
+func (w *window) float64(rnd Rand) (float64, error) {
+	if w.len() == 0 {
+		msg := "cannot draw a random value on an empty distribution."
+		return 0, InvalidDistributionError{S: msg}
+	}
+
+	if w.tree.weighted {
+		return w.tree.sample(randFloat64(rnd) * w.tree.total()), nil
+	}
+	return w.entries[w.head+randIntn(rnd, w.len())].value, nil
+}
+
+// push adds value to the window with the provided weight and time.
+func (w *window) push(value, weight float64, at int64) {
+	w.entries = append(w.entries, windowEntry{value: value, weight: weight, at: at})
+	w.tree.insert(value, weight)
+	w.moments.merge(moments{n: weight, mean: value})
+}
+
+// pop evicts the oldest value from the window.
+func (w *window) pop() {
+	old := w.entries[w.head]
+	w.head++
+	w.tree.remove(old.value)
+
+	// downdating the moments accumulates rounding errors, so they are
+	// recalculated once as many values have been evicted as remain.
+	w.evicted++
+	if w.evicted >= w.len() {
+		w.recalculate()
+	} else {
+		w.moments.remove(moments{n: old.weight, mean: old.value})
+	}
+
+	// reclaim the space used by evicted values.
+	if w.head >= len(w.entries)/2 {
+		n := copy(w.entries, w.entries[w.head:])
+		w.entries = w.entries[:n]
+		w.head = 0
+	}
+}
+
+// recalculate recalculates the moments of the window from its values.
+func (w *window) recalculate() {
+	w.moments = moments{}
+	for _, entry := range w.entries[w.head:] {
+		w.moments.merge(moments{n: entry.weight, mean: entry.value})
+	}
+	w.evicted = 0
+}
+
+// rescale multiplies the weight of every value in the window by f.
+func (w *window) rescale(f float64) {
+	for i := range w.entries {
+		w.entries[i].weight *= f
+	}
+	w.tree.scale(f)
+
+	// the mean is unchanged, while the sums of powers of the differences
+	// from it scale with the weights.
+	w.n, w.m2, w.m3, w.m4 = w.n*f, w.m2*f, w.m3*f, w.m4*f
+}
+
+// len returns the number of values in the window.
+func (w *window) len() int {
+	return len(w.entries) - w.head
+}
@@ -0,0 +1,211 @@
+package godist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_Window_Imp_Moments(t *testing.T) {
+	var _ Moments = &WindowedEmpirical{}
+	var _ Moments = &TimeWindowedEmpirical{}
+	var _ Moments = &DecayedEmpirical{}
+}
+
+// windowStats are the statistics compared between windowed distributions
+// and the equivalent Empirical distribution.
+type windowStats interface {
+	Moments
+	Quantile(p float64) (float64, error)
+}
+
+func compareWindow(t *testing.T, actual, expected windowStats) {
+	t.Helper()
+
+	stats := []struct {
+		Name string
+		A, E func() (float64, error)
+	}{
+		{"mean", actual.Mean, expected.Mean},
+		{"variance", actual.Variance, expected.Variance},
+		{"skewness", actual.Skewness, expected.Skewness},
+		{"kurtosis", actual.ExcessKurtosis, expected.ExcessKurtosis},
+		{"median", actual.Median, expected.Median},
+		{"mode", actual.Mode, expected.Mode},
+	}
+	for _, p := range []float64{0, 0.1, 0.9, 1} {
+		p := p
+		stats = append(stats, struct {
+			Name string
+			A, E func() (float64, error)
+		}{"quantile", func() (float64, error) { return actual.Quantile(p) }, func() (float64, error) { return expected.Quantile(p) }})
+	}
+
+	for _, stat := range stats {
+		e, _ := stat.E()
+		a, err := stat.A()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !floatsEqual(a, e, 1e-9*math.Max(1, math.Abs(e))) {
+			t.Fatalf("expected %v\n got %v for %v\n", e, a, stat.Name)
+		}
+	}
+}
+
+func Test_WindowedEmpirical(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	w, err := NewWindowedEmpirical(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Median(); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	var stream []float64
+	for i := 0; i < 5000; i++ {
+		// a drifting stream, with ties.
+		v := math.Round(10*(float64(i)/100+rnd.NormFloat64())) / 10
+		stream = append(stream, v)
+		w.Add(v)
+
+		if i%250 == 17 || i == 4999 {
+			e := &Empirical{}
+			e.Add(stream[max(0, len(stream)-100):]...)
+			compareWindow(t, w, e)
+
+			if w.Size() != e.Size() {
+				t.Fatalf("expected %v\n got %v\n", e.Size(), w.Size())
+			}
+		}
+	}
+
+	w.Rand = rnd
+	for i := 0; i < 100; i++ {
+		v, _ := w.Float64()
+		found := false
+		for _, s := range stream[len(stream)-100:] {
+			found = found || s == v
+		}
+		if !found {
+			t.Fatalf("expected value in window\n got %v\n", v)
+		}
+	}
+
+	if _, err := NewWindowedEmpirical(0); err == nil {
+		t.Fatal("expected error on invalid size")
+	}
+}
+
+func Test_TimeWindowedEmpirical(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w, err := NewTimeWindowedEmpirical(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Now = func() time.Time { return now }
+
+	w.Add(1, 2)
+	now = now.Add(30 * time.Second)
+	w.Add(3, 4, 5)
+	if m, _ := w.Mean(); m != 3 || w.Size() != 5 {
+		t.Fatalf("expected %v, %v\n got %v, %v\n", 3, 5, m, w.Size())
+	}
+
+	// the first values expire when read.
+	now = now.Add(30 * time.Second)
+	e := &Empirical{}
+	e.Add(3, 4, 5)
+	compareWindow(t, w, e)
+
+	now = now.Add(time.Hour)
+	if w.Size() != 0 {
+		t.Fatalf("expected %v\n got %v\n", 0, w.Size())
+	}
+	if _, err := w.Float64(); err == nil {
+		t.Fatal("expected error on empty distribution")
+	}
+
+	if _, err := NewTimeWindowedEmpirical(0); err == nil {
+		t.Fatal("expected error on invalid duration")
+	}
+}
+
+func Test_DecayedEmpirical(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	const halfLife = 3.0
+
+	// a short half-life rescales the weights many times.
+	d, err := NewDecayedEmpirical(halfLife)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stream []float64
+	for i := 0; i < 3000; i++ {
+		v := float64(rnd.Intn(50)) + float64(i)/100
+		stream = append(stream, v)
+		d.Add(v)
+
+		if i%300 == 17 || i == 2999 {
+			// the weights of the retained values, with the newest having
+			// a weight of one.
+			e := &Empirical{}
+			for age := min(len(stream), decayHalfLives*int(halfLife)) - 1; age >= 0; age-- {
+				e.AddWeighted(stream[len(stream)-1-age], math.Exp2(-float64(age)/halfLife))
+			}
+			compareWindow(t, d, e)
+
+			if !floatsNanoEqual(d.Size(), e.Size()) {
+				t.Fatalf("expected %v\n got %v\n", e.Size(), d.Size())
+			}
+		}
+	}
+
+	// values are drawn in proportion to their weight, so are mostly
+	// recent.
+	d.Rand = rnd
+	recent := 0
+	for i := 0; i < 10000; i++ {
+		v, _ := d.Float64()
+		for _, s := range stream[len(stream)-int(halfLife):] {
+			if s == v {
+				recent++
+				break
+			}
+		}
+	}
+	if p := float64(recent) / 10000; p < 0.45 {
+		t.Fatalf("expected at least %v\n got %v\n", 0.45, p)
+	}
+
+	// at the shortest half-life only the newest value is retained, while
+	// at the longest the weights barely decay.
+	ends := []struct {
+		HalfLife, Mean, Size float64
+	}{
+		{decayMinHalfLife, 5, 1},
+		{decayMaxHalfLife, 2997.0 / 1000, 1000},
+	}
+	for _, end := range ends {
+		d, err := NewDecayedEmpirical(end.HalfLife)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			d.Add(float64(i % 7))
+		}
+		m, err := d.Mean()
+		if err != nil || !floatsNanoEqual(m, end.Mean) || !floatsNanoEqual(d.Size(), end.Size) {
+			t.Fatalf("expected %v, %v\n got %v, %v for half-life %v\n", end.Mean, end.Size, m, d.Size(), end.HalfLife)
+		}
+	}
+
+	for _, h := range []float64{0, -1, decayMinHalfLife / 2, 2 * decayMaxHalfLife, 3e17, math.Inf(1), math.NaN()} {
+		if _, err := NewDecayedEmpirical(h); err == nil {
+			t.Fatalf("expected error for half-life %v\n", h)
+		}
+	}
+}